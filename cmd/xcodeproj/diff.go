@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+)
+
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: xcodeproj diff [flags] <old> <new>\n\nThe old and new projects are .xcodeproj directories or project.pbxproj files.\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "invalid format: %s\n", *format)
+		return exitUsage
+	}
+
+	var contents [][]byte
+	for _, pth := range flags.Args() {
+		content, err := readPBXProj(pth)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			return exitUsage
+		}
+		contents = append(contents, content)
+	}
+
+	diff, err := xcodeproj.DiffPBXProj(contents[0], contents[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to diff projects: %s\n", err)
+		return exitFailure
+	}
+
+	output := []byte(diff.Text())
+	if *format == "json" {
+		if output, err = diff.JSON(); err != nil {
+			fmt.Fprintf(stderr, "failed to render diff: %s\n", err)
+			return exitFailure
+		}
+		output = append(output, '\n')
+	}
+	if _, err := stdout.Write(output); err != nil {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// readPBXProj reads the project.pbxproj file at the path, or in the path if it is an .xcodeproj directory.
+func readPBXProj(pth string) ([]byte, error) {
+	if info, err := os.Stat(pth); err == nil && info.IsDir() {
+		pth = filepath.Join(pth, "project.pbxproj")
+	}
+	content, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", pth, err)
+	}
+	return content, nil
+}
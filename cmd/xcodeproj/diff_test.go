@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldPth := writeTestProject(t, dir, "Old", testhelper.XcodeProjectTest)
+	newPth := filepath.Join(writeTestProject(t, dir, "New", strings.Replace(testhelper.XcodeProjectTest, "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProj;", "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.App;", -1)), "project.pbxproj")
+	invalidPth := filepath.Join(dir, "invalid.pbxproj")
+	require.NoError(t, ioutil.WriteFile(invalidPth, []byte("// !$*UTF8*$!\n{\n"), 0644))
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+		wantStdout   string
+		wantStderr   string
+	}{
+		{
+			name:         "text",
+			args:         []string{oldPth, newPth},
+			wantExitCode: exitOK,
+			wantStdout: `Target XcodeProj:
+  ~ [Debug] PRODUCT_BUNDLE_IDENTIFIER: com.bitrise.XcodeProj -> com.bitrise.App
+  ~ [Release] PRODUCT_BUNDLE_IDENTIFIER: com.bitrise.XcodeProj -> com.bitrise.App
+`,
+		},
+		{
+			name:         "json",
+			args:         []string{"--format", "json", oldPth, oldPth},
+			wantExitCode: exitOK,
+			wantStdout: `{
+  "changes": []
+}
+`,
+		},
+		{
+			name:         "invalid format",
+			args:         []string{"--format", "xml", oldPth, newPth},
+			wantExitCode: exitUsage,
+			wantStderr:   "invalid format: xml\n",
+		},
+		{
+			name:         "missing project",
+			args:         []string{oldPth, filepath.Join(dir, "Missing.xcodeproj")},
+			wantExitCode: exitUsage,
+			wantStderr:   "failed to read",
+		},
+		{
+			name:         "invalid project",
+			args:         []string{oldPth, invalidPth},
+			wantExitCode: exitFailure,
+			wantStderr:   "failed to diff projects",
+		},
+		{
+			name:         "one project",
+			args:         []string{oldPth},
+			wantExitCode: exitUsage,
+			wantStderr:   "Usage: xcodeproj diff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.Equal(t, tt.wantExitCode, runDiff(tt.args, &stdout, &stderr), stderr.String())
+			require.Equal(t, tt.wantStdout, stdout.String())
+			require.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+)
+
+type lintResult struct {
+	Project string                      `json:"project"`
+	Issues  []xcodeproj.ValidationIssue `json:"issues"`
+}
+
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "output format: text or json")
+	failOn := flags.String("fail-on", string(xcodeproj.ErrorValidationSeverity), "lowest issue severity failing the command: error or warning")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: xcodeproj lint [flags] <project.xcodeproj>...\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "invalid format: %s\n", *format)
+		return exitUsage
+	}
+
+	failingSeverities := map[xcodeproj.ValidationSeverity]bool{xcodeproj.ErrorValidationSeverity: true}
+	switch xcodeproj.ValidationSeverity(*failOn) {
+	case xcodeproj.ErrorValidationSeverity:
+	case xcodeproj.WarningValidationSeverity:
+		failingSeverities[xcodeproj.WarningValidationSeverity] = true
+	default:
+		fmt.Fprintf(stderr, "invalid fail-on severity: %s\n", *failOn)
+		return exitUsage
+	}
+
+	// a project, which can not be opened, fails the lint with a parse-error issue (see xcodeproj.ValidateProject)
+	var results []lintResult
+	for _, pth := range flags.Args() {
+		issues, err := xcodeproj.ValidateProject(pth)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to validate project (%s): %s\n", pth, err)
+			return exitFailure
+		}
+		results = append(results, lintResult{Project: pth, Issues: issues})
+	}
+
+	if err := printLintResults(stdout, *format, results); err != nil {
+		fmt.Fprintf(stderr, "failed to print results: %s\n", err)
+		return exitFailure
+	}
+
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if failingSeverities[issue.Severity] {
+				return exitFailure
+			}
+		}
+	}
+	return exitOK
+}
+
+func printLintResults(w io.Writer, format string, results []lintResult) error {
+	if format == "json" {
+		for i := range results {
+			if results[i].Issues == nil {
+				results[i].Issues = []xcodeproj.ValidationIssue{}
+			}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	for _, result := range results {
+		for _, issue := range result.Issues {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", result.Project, issue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestProject(t *testing.T, dir, name, content string) string {
+	projectPth := filepath.Join(dir, name+".xcodeproj")
+	require.NoError(t, os.MkdirAll(projectPth, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(content), 0644))
+	return projectPth
+}
+
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+	validPth := writeTestProject(t, dir, "Valid", testhelper.XcodeProjectTest)
+	invalidPth := writeTestProject(t, dir, "Invalid", "// !$*UTF8*$!\n{\n\tobjects = {\n")
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+		wantStdout   []string
+		wantStderr   string
+	}{
+		{
+			name:         "no projects",
+			args:         nil,
+			wantExitCode: exitUsage,
+			wantStderr:   "Usage: xcodeproj lint",
+		},
+		{
+			name:         "invalid format",
+			args:         []string{"--format", "xml", validPth},
+			wantExitCode: exitUsage,
+			wantStderr:   "invalid format: xml",
+		},
+		{
+			name:         "warnings only",
+			args:         []string{validPth},
+			wantExitCode: exitOK,
+			wantStdout:   []string{validPth + ": warning: missing-file (7D5B35FF20E28EE80022BAE6)"},
+		},
+		{
+			name:         "failing on warnings",
+			args:         []string{"--fail-on", "warning", validPth},
+			wantExitCode: exitFailure,
+			wantStdout:   []string{validPth + ": warning: missing-file"},
+		},
+		{
+			name:         "project can not be parsed",
+			args:         []string{validPth, invalidPth},
+			wantExitCode: exitFailure,
+			wantStdout:   []string{validPth + ": warning: missing-file", invalidPth + ": error: parse-error"},
+		},
+		{
+			name:         "project does not exist",
+			args:         []string{filepath.Join(dir, "Missing.xcodeproj")},
+			wantExitCode: exitFailure,
+			wantStderr:   "failed to validate project",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.Equal(t, tt.wantExitCode, runLint(tt.args, &stdout, &stderr), stderr.String())
+			for _, want := range tt.wantStdout {
+				require.Contains(t, stdout.String(), want)
+			}
+			require.Contains(t, stderr.String(), tt.wantStderr)
+		})
+	}
+
+	t.Log("json format")
+	{
+		var stdout, stderr bytes.Buffer
+		require.Equal(t, exitFailure, runLint([]string{"--format", "json", invalidPth}, &stdout, &stderr))
+
+		var results []lintResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		require.Equal(t, 1, len(results))
+		require.Equal(t, invalidPth, results[0].Project)
+		require.Equal(t, 1, len(results[0].Issues))
+		require.Equal(t, "parse-error", string(results[0].Issues[0].Code))
+	}
+}
@@ -0,0 +1,59 @@
+// Command xcodeproj runs checks and maintenance tasks on Xcode projects.
+//
+// Usage:
+//
+//	xcodeproj <command> [flags] [arguments]
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+type command struct {
+	description string
+	run         func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"diff":  {description: "summarize the changes between two versions of a project", run: runDiff},
+	"lint":  {description: "check the integrity of the projects", run: runLint},
+	"merge": {description: "three-way merge project.pbxproj files, usable as a git merge driver", run: runMerge},
+	"sort":  {description: "sort the groups, the Sources build phases and the objects of the projects", run: runSort},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: xcodeproj <command> [flags] [arguments]\n\nCommands:\n")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].description)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", os.Args[1])
+		usage()
+		os.Exit(exitUsage)
+	}
+	os.Exit(cmd.run(os.Args[2:], os.Stdout, os.Stderr))
+}
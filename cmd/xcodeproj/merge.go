@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+)
+
+// runMerge implements a git merge driver, register it like:
+//
+//	git config merge.pbxproj.driver "xcodeproj merge %O %A %B %P"
+//	echo "*.pbxproj merge=pbxproj" >> .gitattributes
+//
+// The merged contents are written to ours' file. On conflicts ours' values are kept,
+// the conflicts are printed to the stderr and the command fails, so git marks the file as conflicted.
+func runMerge(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: xcodeproj merge <base> <ours> <theirs> [<path of the project.pbxproj>]\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 3 && flags.NArg() != 4 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	basePth, oursPth, theirsPth := flags.Arg(0), flags.Arg(1), flags.Arg(2)
+	projectName := ""
+	if flags.NArg() == 4 {
+		projectDir := filepath.Dir(flags.Arg(3))
+		projectName = strings.TrimSuffix(filepath.Base(projectDir), filepath.Ext(projectDir))
+	}
+
+	var contents [][]byte
+	for _, pth := range []string{basePth, oursPth, theirsPth} {
+		content, err := ioutil.ReadFile(pth)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to read %s: %s\n", pth, err)
+			return exitUsage
+		}
+		contents = append(contents, content)
+	}
+
+	merged, conflicts, err := xcodeproj.MergePBXProj(contents[0], contents[1], contents[2], projectName)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to merge: %s\n", err)
+		return exitFailure
+	}
+
+	if err := ioutil.WriteFile(oursPth, merged, 0644); err != nil {
+		fmt.Fprintf(stderr, "failed to write %s: %s\n", oursPth, err)
+		return exitFailure
+	}
+
+	if len(conflicts) > 0 {
+		fmt.Fprintf(stderr, "%d conflict(s), ours' values are kept:\n", len(conflicts))
+		for _, conflict := range conflicts {
+			fmt.Fprintf(stderr, "  %s\n", conflict)
+		}
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMerge(t *testing.T) {
+	base := testhelper.XcodeProjectTest
+	upgraded := strings.Replace(base, "LastUpgradeCheck = 0940;", "LastUpgradeCheck = 1000;", 1)
+	swiftUpdated := strings.Replace(base, "LastSwiftUpdateCheck = 0940;", "LastSwiftUpdateCheck = 1000;", 1)
+	otherUpgraded := strings.Replace(base, "LastUpgradeCheck = 0940;", "LastUpgradeCheck = 1010;", 1)
+
+	tests := []struct {
+		name         string
+		ours         string
+		theirs       string
+		missingBase  bool
+		wantExitCode int
+		wantOurs     []string
+		wantStderr   string
+	}{
+		{
+			name:         "clean merge",
+			ours:         upgraded,
+			theirs:       swiftUpdated,
+			wantExitCode: exitOK,
+			wantOurs:     []string{"LastUpgradeCheck = 1000;", "LastSwiftUpdateCheck = 1000;"},
+		},
+		{
+			name:         "conflicts",
+			ours:         upgraded,
+			theirs:       otherUpgraded,
+			wantExitCode: exitFailure,
+			wantOurs:     []string{"LastUpgradeCheck = 1000;"},
+			wantStderr:   "1 conflict(s), ours' values are kept:\n  7D5B35F420E28EE80022BAE6.attributes.LastUpgradeCheck: base: 0940, ours: 1000, theirs: 1010\n",
+		},
+		{
+			name:         "missing base",
+			ours:         upgraded,
+			theirs:       swiftUpdated,
+			missingBase:  true,
+			wantExitCode: exitUsage,
+			wantStderr:   "failed to read",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			basePth, oursPth, theirsPth := filepath.Join(dir, "base"), filepath.Join(dir, "ours"), filepath.Join(dir, "theirs")
+			if !tt.missingBase {
+				require.NoError(t, ioutil.WriteFile(basePth, []byte(base), 0644))
+			}
+			require.NoError(t, ioutil.WriteFile(oursPth, []byte(tt.ours), 0644))
+			require.NoError(t, ioutil.WriteFile(theirsPth, []byte(tt.theirs), 0644))
+
+			var stdout, stderr bytes.Buffer
+			args := []string{basePth, oursPth, theirsPth, filepath.Join(dir, "XcodeProj.xcodeproj", "project.pbxproj")}
+			require.Equal(t, tt.wantExitCode, runMerge(args, &stdout, &stderr), stderr.String())
+			require.Contains(t, stderr.String(), tt.wantStderr)
+
+			merged, err := ioutil.ReadFile(oursPth)
+			require.NoError(t, err)
+			for _, want := range tt.wantOurs {
+				require.Contains(t, string(merged), want)
+			}
+		})
+	}
+
+	t.Log("wrong number of arguments")
+	{
+		var stdout, stderr bytes.Buffer
+		require.Equal(t, exitUsage, runMerge([]string{"base", "ours"}, &stdout, &stderr))
+		require.Contains(t, stderr.String(), "Usage: xcodeproj merge")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+)
+
+func runSort(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("sort", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	check := flags.Bool("check", false, "do not modify the projects, fail if any of them is not sorted")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: xcodeproj sort [flags] <project.xcodeproj>...\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	exitCode := exitOK
+	for _, pth := range flags.Args() {
+		proj, err := xcodeproj.Open(pth)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to open project (%s): %s\n", pth, err)
+			return exitUsage
+		}
+
+		changed, err := proj.Sort()
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to sort project (%s): %s\n", pth, err)
+			return exitFailure
+		}
+		if !changed {
+			continue
+		}
+
+		if *check {
+			fmt.Fprintf(stdout, "%s: not sorted\n", pth)
+			exitCode = exitFailure
+			continue
+		}
+
+		if err := proj.Save(); err != nil {
+			fmt.Fprintf(stderr, "failed to save project (%s): %s\n", pth, err)
+			return exitFailure
+		}
+		fmt.Fprintf(stdout, "%s: sorted\n", pth)
+	}
+	return exitCode
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSort(t *testing.T) {
+	// move a build file to the end of its section
+	line := "\t\t7D03431A20F4BB070050B6A6 /* TodayExtension.appex in Embed App Extensions */ = {isa = PBXBuildFile; fileRef = 7D03430D20F4BB070050B6A6 /* TodayExtension.appex */; settings = {ATTRIBUTES = (RemoveHeadersOnCopy, ); }; };\n"
+	unsorted := strings.Replace(testhelper.XcodeProjectTest, line, "", 1)
+	unsorted = strings.Replace(unsorted, "/* End PBXBuildFile section */", line+"/* End PBXBuildFile section */", 1)
+
+	dir := t.TempDir()
+	projectPth := writeTestProject(t, dir, "XcodeProj", unsorted)
+	pbxProjPth := filepath.Join(projectPth, "project.pbxproj")
+
+	readPBXProj := func(t *testing.T) string {
+		content, err := ioutil.ReadFile(pbxProjPth)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	// the steps run in order, on the same project
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+		wantStdout   string
+		wantStderr   string
+		wantSorted   bool
+	}{
+		{
+			name:         "no projects",
+			wantExitCode: exitUsage,
+			wantStderr:   "Usage: xcodeproj sort",
+		},
+		{
+			name:         "missing project",
+			args:         []string{filepath.Join(dir, "Missing.xcodeproj")},
+			wantExitCode: exitUsage,
+			wantStderr:   "failed to open project",
+		},
+		{
+			name:         "check unsorted project",
+			args:         []string{"--check", projectPth},
+			wantExitCode: exitFailure,
+			wantStdout:   projectPth + ": not sorted\n",
+		},
+		{
+			name:         "sort",
+			args:         []string{projectPth},
+			wantExitCode: exitOK,
+			wantStdout:   projectPth + ": sorted\n",
+			wantSorted:   true,
+		},
+		{
+			name:         "check sorted project",
+			args:         []string{"--check", projectPth},
+			wantExitCode: exitOK,
+			wantSorted:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.Equal(t, tt.wantExitCode, runSort(tt.args, &stdout, &stderr), stderr.String())
+			require.Equal(t, tt.wantStdout, stdout.String())
+			require.Contains(t, stderr.String(), tt.wantStderr)
+
+			if tt.wantSorted {
+				require.NotEqual(t, unsorted, readPBXProj(t))
+			} else {
+				require.Equal(t, unsorted, readPBXProj(t))
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package swiftpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+)
+
+// PackageResolved represents a Package.resolved file, the pinned versions of the Swift package dependencies.
+type PackageResolved struct {
+	// Version is the version of the file format: 1, 2 or 3.
+	Version    int
+	OriginHash string
+	Pins       []Pin
+}
+
+// Pin is the resolved state of a package dependency.
+type Pin struct {
+	// Identity is the package's identity, the lowercased last path component of its location (like: alamofire).
+	Identity string
+	// Kind is the kind of the package, like: remoteSourceControl or localSourceControl (empty in format version 1).
+	Kind     string
+	Location string
+	State    PinState
+}
+
+// PinState ...
+type PinState struct {
+	Version  string
+	Branch   string
+	Revision string
+}
+
+type packageResolvedV1 struct {
+	Object struct {
+		Pins []struct {
+			Package       string   `json:"package"`
+			RepositoryURL string   `json:"repositoryURL"`
+			State         pinState `json:"state"`
+		} `json:"pins"`
+	} `json:"object"`
+	Version int `json:"version"`
+}
+
+type packageResolvedV2 struct {
+	OriginHash string `json:"originHash"`
+	Pins       []struct {
+		Identity string   `json:"identity"`
+		Kind     string   `json:"kind"`
+		Location string   `json:"location"`
+		State    pinState `json:"state"`
+	} `json:"pins"`
+	Version int `json:"version"`
+}
+
+type pinState struct {
+	Version  *string `json:"version"`
+	Branch   *string `json:"branch"`
+	Revision *string `json:"revision"`
+}
+
+func (s pinState) pinState() PinState {
+	var state PinState
+	for _, value := range []struct {
+		from *string
+		to   *string
+	}{
+		{s.Version, &state.Version},
+		{s.Branch, &state.Branch},
+		{s.Revision, &state.Revision},
+	} {
+		if value.from != nil {
+			*value.to = *value.from
+		}
+	}
+	return state
+}
+
+// ParsePackageResolved parses the contents of a Package.resolved file (format version 1, 2 or 3).
+func ParsePackageResolved(content []byte) (PackageResolved, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(content, &header); err != nil {
+		return PackageResolved{}, fmt.Errorf("failed to unmarshal Package.resolved: %s", err)
+	}
+
+	switch header.Version {
+	case 1:
+		var v1 packageResolvedV1
+		if err := json.Unmarshal(content, &v1); err != nil {
+			return PackageResolved{}, fmt.Errorf("failed to unmarshal Package.resolved: %s", err)
+		}
+
+		resolved := PackageResolved{Version: v1.Version}
+		for _, pin := range v1.Object.Pins {
+			resolved.Pins = append(resolved.Pins, Pin{
+				Identity: Identity(pin.RepositoryURL),
+				Location: pin.RepositoryURL,
+				State:    pin.State.pinState(),
+			})
+		}
+		return resolved, nil
+	case 2, 3:
+		var v2 packageResolvedV2
+		if err := json.Unmarshal(content, &v2); err != nil {
+			return PackageResolved{}, fmt.Errorf("failed to unmarshal Package.resolved: %s", err)
+		}
+
+		resolved := PackageResolved{Version: v2.Version, OriginHash: v2.OriginHash}
+		for _, pin := range v2.Pins {
+			resolved.Pins = append(resolved.Pins, Pin{
+				Identity: pin.Identity,
+				Kind:     pin.Kind,
+				Location: pin.Location,
+				State:    pin.State.pinState(),
+			})
+		}
+		return resolved, nil
+	default:
+		return PackageResolved{}, fmt.Errorf("unsupported Package.resolved version: %d", header.Version)
+	}
+}
+
+// OpenPackageResolved parses the Package.resolved file at the given path.
+func OpenPackageResolved(pth string) (PackageResolved, error) {
+	content, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return PackageResolved{}, err
+	}
+
+	resolved, err := ParsePackageResolved(content)
+	if err != nil {
+		return PackageResolved{}, fmt.Errorf("failed to parse %s: %s", pth, err)
+	}
+	return resolved, nil
+}
+
+// Pin returns the pin of the package with the given location (repository URL).
+// The locations are compared by the package identity, like SwiftPM does.
+func (r PackageResolved) Pin(location string) (Pin, bool) {
+	identity := Identity(location)
+	for _, pin := range r.Pins {
+		pinIdentity := pin.Identity
+		if pinIdentity == "" {
+			pinIdentity = Identity(pin.Location)
+		}
+		if pinIdentity == identity {
+			return pin, true
+		}
+	}
+	return Pin{}, false
+}
+
+// Identity returns the package identity of the location:
+// the lowercased last path component without the .git extension, like: https://github.com/Alamofire/Alamofire.git -> alamofire
+func Identity(location string) string {
+	location = strings.TrimRight(location, "/")
+	location = strings.TrimSuffix(location, ".git")
+	return strings.ToLower(path.Base(location))
+}
@@ -0,0 +1,143 @@
+package swiftpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageResolved(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    PackageResolved
+		wantErr string
+	}{
+		{
+			name:    "version 1",
+			content: packageResolvedV1Content,
+			want: PackageResolved{
+				Version: 1,
+				Pins: []Pin{
+					{Identity: "alamofire", Location: "https://github.com/Alamofire/Alamofire.git", State: PinState{Version: "5.8.1", Revision: "3dc6a42c7727c49bf26508e29b0a0b35f9c7e1ad"}},
+					{Identity: "swift-log", Location: "https://github.com/apple/swift-log.git", State: PinState{Branch: "main", Revision: "532d8b529501fb73a2455b179e0bbb6d49b652ed"}},
+				},
+			},
+		},
+		{
+			name:    "version 2",
+			content: packageResolvedV2Content,
+			want: PackageResolved{
+				Version: 2,
+				Pins: []Pin{
+					{Identity: "alamofire", Kind: "remoteSourceControl", Location: "https://github.com/Alamofire/Alamofire.git", State: PinState{Version: "5.8.1", Revision: "3dc6a42c7727c49bf26508e29b0a0b35f9c7e1ad"}},
+				},
+			},
+		},
+		{
+			name:    "version 3",
+			content: packageResolvedV3Content,
+			want: PackageResolved{
+				Version:    3,
+				OriginHash: "1ae6cd85e3d1a3e5c0d4bcd3b1e40f5d2d8d9a8b4c30b52a07b1d1a9bfa5f2d2",
+				Pins: []Pin{
+					{Identity: "swift-log", Kind: "remoteSourceControl", Location: "https://github.com/apple/swift-log.git", State: PinState{Version: "1.5.3", Revision: "532d8b529501fb73a2455b179e0bbb6d49b652ed"}},
+				},
+			},
+		},
+		{
+			name:    "unsupported version",
+			content: `{"version": 4}`,
+			wantErr: "unsupported Package.resolved version: 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePackageResolved([]byte(tt.content))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPackageResolved_Pin(t *testing.T) {
+	resolved, err := ParsePackageResolved([]byte(packageResolvedV1Content))
+	require.NoError(t, err)
+
+	pin, ok := resolved.Pin("https://github.com/alamofire/alamofire")
+	require.True(t, ok)
+	require.Equal(t, "5.8.1", pin.State.Version)
+
+	_, ok = resolved.Pin("https://github.com/apple/swift-nio.git")
+	require.False(t, ok)
+}
+
+func TestIdentity(t *testing.T) {
+	require.Equal(t, "alamofire", Identity("https://github.com/Alamofire/Alamofire.git"))
+	require.Equal(t, "swift-log", Identity("git@github.com:apple/swift-log.git"))
+	require.Equal(t, "core", Identity("../Packages/Core/"))
+}
+
+const packageResolvedV1Content = `{
+  "object": {
+    "pins": [
+      {
+        "package": "Alamofire",
+        "repositoryURL": "https://github.com/Alamofire/Alamofire.git",
+        "state": {
+          "branch": null,
+          "revision": "3dc6a42c7727c49bf26508e29b0a0b35f9c7e1ad",
+          "version": "5.8.1"
+        }
+      },
+      {
+        "package": "swift-log",
+        "repositoryURL": "https://github.com/apple/swift-log.git",
+        "state": {
+          "branch": "main",
+          "revision": "532d8b529501fb73a2455b179e0bbb6d49b652ed",
+          "version": null
+        }
+      }
+    ]
+  },
+  "version": 1
+}
+`
+
+const packageResolvedV2Content = `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : {
+        "revision" : "3dc6a42c7727c49bf26508e29b0a0b35f9c7e1ad",
+        "version" : "5.8.1"
+      }
+    }
+  ],
+  "version" : 2
+}
+`
+
+const packageResolvedV3Content = `{
+  "originHash" : "1ae6cd85e3d1a3e5c0d4bcd3b1e40f5d2d8d9a8b4c30b52a07b1d1a9bfa5f2d2",
+  "pins" : [
+    {
+      "identity" : "swift-log",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/apple/swift-log.git",
+      "state" : {
+        "revision" : "532d8b529501fb73a2455b179e0bbb6d49b652ed",
+        "version" : "1.5.3"
+      }
+    }
+  ],
+  "version" : 3
+}
+`
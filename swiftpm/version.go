@@ -0,0 +1,106 @@
+package swiftpm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, like: 5.8.0 or 1.0.0-beta.1
+type Version struct {
+	Major, Minor, Patch int
+	PreRelease          string
+}
+
+// ParseVersion parses a semantic version, the missing minor and patch components default to 0.
+// The build metadata (+...) is ignored.
+func ParseVersion(s string) (Version, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if idx := strings.Index(raw, "+"); idx != -1 {
+		raw = raw[:idx]
+	}
+
+	var version Version
+	if idx := strings.Index(raw, "-"); idx != -1 {
+		version.PreRelease = raw[idx+1:]
+		raw = raw[:idx]
+	}
+
+	components := strings.Split(raw, ".")
+	if len(components) > 3 {
+		return Version{}, fmt.Errorf("invalid version: %s", s)
+	}
+	for i, target := range []*int{&version.Major, &version.Minor, &version.Patch} {
+		if i >= len(components) {
+			break
+		}
+		n, err := strconv.Atoi(components[i])
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version: %s", s)
+		}
+		*target = n
+	}
+
+	return version, nil
+}
+
+// String ...
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 if the version is lower than, equal to or greater than the other version.
+// A pre-release version is lower than the corresponding release version.
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	switch {
+	case v.PreRelease == other.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case other.PreRelease == "":
+		return -1
+	}
+
+	identifiers := strings.Split(v.PreRelease, ".")
+	otherIdentifiers := strings.Split(other.PreRelease, ".")
+	for i := 0; i < len(identifiers) && i < len(otherIdentifiers); i++ {
+		if c := comparePreReleaseIdentifiers(identifiers[i], otherIdentifiers[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(identifiers), len(otherIdentifiers))
+}
+
+func comparePreReleaseIdentifiers(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(aNum, bNum)
+	case aErr == nil:
+		// numeric identifiers have lower precedence than alphanumeric ones
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package swiftpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    Version
+		wantErr bool
+	}{
+		{version: "5.8.1", want: Version{Major: 5, Minor: 8, Patch: 1}},
+		{version: "v2.0", want: Version{Major: 2}},
+		{version: "1.0.0-beta.2+exp.sha.5114f85", want: Version{Major: 1, PreRelease: "beta.2"}},
+		{version: "1.x", wantErr: true},
+		{version: "1.2.3.4", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := ParseVersion(tt.version)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.2.3", b: "1.2.3", want: 0},
+		{a: "1.2.3", b: "1.10.0", want: -1},
+		{a: "2.0.0", b: "1.99.99", want: 1},
+		{a: "1.0.0-alpha", b: "1.0.0", want: -1},
+		{a: "1.0.0-alpha.1", b: "1.0.0-alpha", want: 1},
+		{a: "1.0.0-alpha.2", b: "1.0.0-alpha.10", want: -1},
+		{a: "1.0.0-rc.1", b: "1.0.0-beta.11", want: 1},
+		{a: "1.0.0-1", b: "1.0.0-alpha", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, err := ParseVersion(tt.a)
+			require.NoError(t, err)
+			b, err := ParseVersion(tt.b)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, a.Compare(b))
+		})
+	}
+}
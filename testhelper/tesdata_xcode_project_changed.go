@@ -225,53 +225,53 @@ const XcodeProjectTestChanged = `// !$*UTF8*$!
 
 /* Begin PBXProject section */
 		7D5B35F420E28EE80022BAE6 /* Project object */ = {
-	attributes = {
-		LastSwiftUpdateCheck = 0940;
-		LastUpgradeCheck = 0940;
-		ORGANIZATIONNAME = Bitrise;
-		TargetAttributes = {
-			7D0342F020F4BA280050B6A6 = {
-				CreatedOnToolsVersion = "9.4.1";
-				TestTargetID = 7D5B35FB20E28EE80022BAE6;
-			};
-			7D03430C20F4BB070050B6A6 = {
-				CreatedOnToolsVersion = "9.4.1";
-				SystemCapabilities = {
-					"com.apple.Push" = {
-						enabled = 1;
+			isa = PBXProject;
+			attributes = {
+				LastSwiftUpdateCheck = 0940;
+				LastUpgradeCheck = 0940;
+				ORGANIZATIONNAME = Bitrise;
+				TargetAttributes = {
+					7D0342F020F4BA280050B6A6 = {
+						CreatedOnToolsVersion = 9.4.1;
+						TestTargetID = 7D5B35FB20E28EE80022BAE6;
+					};
+					7D03430C20F4BB070050B6A6 = {
+						CreatedOnToolsVersion = 9.4.1;
+						SystemCapabilities = {
+							com.apple.Push = {
+								enabled = 1;
+							};
+							com.apple.iCloud = {
+								enabled = 1;
+							};
+						};
 					};
-					"com.apple.iCloud" = {
-						enabled = 1;
+					7D5B35FB20E28EE80022BAE6 = {
+						CreatedOnToolsVersion = 9.4.1;
+						DevelopmentTeam = ABCD1234;
+						DevelopmentTeamName = "";
+						ProvisioningStyle = Manual;
 					};
 				};
 			};
-			7D5B35FB20E28EE80022BAE6 = {
-				CreatedOnToolsVersion = "9.4.1";
-				DevelopmentTeam = ABCD1234;
-				DevelopmentTeamName = "";
-				ProvisioningStyle = Manual;
-			};
+			buildConfigurationList = 7D5B35F720E28EE80022BAE6 /* Build configuration list for PBXProject "XcodeProj" */;
+			compatibilityVersion = "Xcode 9.3";
+			developmentRegion = en;
+			hasScannedForEncodings = 0;
+			knownRegions = (
+				en,
+				Base,
+			);
+			mainGroup = 7D5B35F320E28EE80022BAE6;
+			productRefGroup = 7D5B35FD20E28EE80022BAE6 /* Products */;
+			projectDirPath = "";
+			projectRoot = "";
+			targets = (
+				7D5B35FB20E28EE80022BAE6 /* XcodeProj */,
+				7D0342F020F4BA280050B6A6 /* XcodeProjUITests */,
+				7D03430C20F4BB070050B6A6 /* TodayExtension */,
+			);
 		};
-	};
-	buildConfigurationList = 7D5B35F720E28EE80022BAE6;
-	compatibilityVersion = "Xcode 9.3";
-	developmentRegion = en;
-	hasScannedForEncodings = 0;
-	isa = PBXProject;
-	knownRegions = (
-		en,
-		Base,
-	);
-	mainGroup = 7D5B35F320E28EE80022BAE6;
-	productRefGroup = 7D5B35FD20E28EE80022BAE6;
-	projectDirPath = "";
-	projectRoot = "";
-	targets = (
-		7D5B35FB20E28EE80022BAE6,
-		7D0342F020F4BA280050B6A6,
-		7D03430C20F4BB070050B6A6,
-	);
-};
 /* End PBXProject section */
 
 /* Begin PBXResourcesBuildPhase section */
@@ -564,27 +564,27 @@ const XcodeProjectTestChanged = `// !$*UTF8*$!
 			name = Release;
 		};
 		7D5B360F20E28EEA0022BAE6 /* Debug */ = {
-	buildSettings = {
-		"ALWAYS_EMBED_SWIFT_STANDARD_LIBRARIES" = YES;
-		"ASSETCATALOG_COMPILER_APPICON_NAME" = AppIcon;
-		"CODE_SIGN_IDENTITY" = "Apple Development: John Doe (ASDF1234)";
-		"CODE_SIGN_STYLE" = Manual;
-		"DEVELOPMENT_TEAM" = ABCD1234;
-		"INFOPLIST_FILE" = "XcodeProj/Info.plist";
-		"LD_RUNPATH_SEARCH_PATHS" = (
-			"$(inherited)",
-			"@executable_path/Frameworks",
-		);
-		"PRODUCT_BUNDLE_IDENTIFIER" = "com.bitrise.XcodeProj";
-		"PRODUCT_NAME" = "$(TARGET_NAME)";
-		"PROVISIONING_PROFILE" = "asdf56b6-e75a-4f86-bf25-101bfc2fasdf";
-		"PROVISIONING_PROFILE_SPECIFIER" = "";
-		"SWIFT_VERSION" = "4.0";
-		"TARGETED_DEVICE_FAMILY" = "1,2";
-	};
-	isa = XCBuildConfiguration;
-	name = Debug;
-};
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				ALWAYS_EMBED_SWIFT_STANDARD_LIBRARIES = YES;
+				ASSETCATALOG_COMPILER_APPICON_NAME = AppIcon;
+				CODE_SIGN_IDENTITY = "Apple Development: John Doe (ASDF1234)";
+				CODE_SIGN_STYLE = Manual;
+				DEVELOPMENT_TEAM = ABCD1234;
+				INFOPLIST_FILE = XcodeProj/Info.plist;
+				LD_RUNPATH_SEARCH_PATHS = (
+					"$(inherited)",
+					"@executable_path/Frameworks",
+				);
+				PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProj;
+				PRODUCT_NAME = "$(TARGET_NAME)";
+				PROVISIONING_PROFILE = "asdf56b6-e75a-4f86-bf25-101bfc2fasdf";
+				PROVISIONING_PROFILE_SPECIFIER = "";
+				SWIFT_VERSION = 4.0;
+				TARGETED_DEVICE_FAMILY = "1,2";
+			};
+			name = Debug;
+		};
 		7D5B361020E28EEA0022BAE6 /* Release */ = {
 			isa = XCBuildConfiguration;
 			buildSettings = {
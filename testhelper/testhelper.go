@@ -1,6 +1,7 @@
 package testhelper
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -49,3 +50,11 @@ func CreateTmpFile(t *testing.T, name, content string) string {
 	require.NoError(t, fileutil.WriteStringToFile(pth, content))
 	return pth
 }
+
+// CreateFileInDir creates a file with the given content at dir/name, the missing parent directories are created too.
+func CreateFileInDir(t *testing.T, dir, name, content string) string {
+	pth := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+	require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	return pth
+}
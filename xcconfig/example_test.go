@@ -0,0 +1,13 @@
+package xcconfig
+
+import "fmt"
+
+func Example() {
+	config, err := Open("Config/Release.xcconfig")
+	if err != nil {
+		panic(err)
+	}
+
+	settings := config.Evaluate(map[string]string{"sdk": "iphoneos", "config": "Release"})
+	fmt.Printf("bundle id: %s\n", settings["PRODUCT_BUNDLE_IDENTIFIER"])
+}
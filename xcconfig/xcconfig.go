@@ -0,0 +1,262 @@
+package xcconfig
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// Condition is a build setting condition, like: sdk=iphoneos*
+type Condition struct {
+	Name    string
+	Pattern string
+}
+
+// Conditions ...
+type Conditions []Condition
+
+// String returns the conditions in the build setting key format, like: [sdk=iphoneos*][arch=arm64]
+func (c Conditions) String() string {
+	var s string
+	for _, condition := range c {
+		s += fmt.Sprintf("[%s=%s]", condition.Name, condition.Pattern)
+	}
+	return s
+}
+
+// Match reports whether every condition matches the given condition values,
+// like: {"sdk": "iphoneos14.5", "arch": "arm64", "config": "Debug"}.
+// A condition without value never matches.
+func (c Conditions) Match(values map[string]string) bool {
+	for _, condition := range c {
+		value := values[condition.Name]
+		if value == "" {
+			return false
+		}
+		if matched, err := path.Match(condition.Pattern, value); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseKey splits a conditional build setting key (KEY[sdk=iphoneos*][arch=arm64]) to the setting name and its conditions.
+func ParseKey(key string) (string, Conditions) {
+	idx := strings.Index(key, "[")
+	if idx == -1 {
+		return strings.TrimSpace(key), nil
+	}
+
+	var conditions Conditions
+	for _, rawCondition := range strings.Split(strings.TrimSuffix(key[idx+1:], "]"), "[") {
+		rawCondition = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(rawCondition), "]"))
+		split := strings.SplitN(rawCondition, "=", 2)
+		if len(split) != 2 {
+			continue
+		}
+		conditions = append(conditions, Condition{Name: strings.TrimSpace(split[0]), Pattern: strings.TrimSpace(split[1])})
+	}
+
+	return strings.TrimSpace(key[:idx]), conditions
+}
+
+// Assignment represents a build setting assignment line: KEY[sdk=iphoneos*] = VALUE
+type Assignment struct {
+	Key        string
+	Conditions Conditions
+	Value      string
+
+	// Path is the path of the xcconfig file containing the assignment.
+	Path string
+	Line int
+}
+
+// FullKey returns the key with its conditions, like: KEY[sdk=iphoneos*]
+func (a Assignment) FullKey() string {
+	return a.Key + a.Conditions.String()
+}
+
+// Config represents an xcconfig file.
+type Config struct {
+	Path string
+	// Assignments lists the assignments in evaluation order, the included files' assignments are inlined.
+	Assignments []Assignment
+	// Includes lists the absolute path of the included files (in include order), the missing optional includes are skipped.
+	Includes []string
+}
+
+// Open parses the xcconfig file at the given path, including the files it includes.
+func Open(pth string) (Config, error) {
+	absPth, err := filepath.Abs(pth)
+	if err != nil {
+		return Config{}, err
+	}
+
+	content, err := fileutil.ReadBytesFromFile(absPth)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Parse(content, absPth)
+}
+
+// Parse parses the content of an xcconfig file residing at the given path,
+// the relative #include directives are resolved relative to the file's directory.
+func Parse(content []byte, pth string) (Config, error) {
+	config := Config{Path: pth}
+	if err := config.parse(content, pth, map[string]bool{}); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+func (c *Config) parse(content []byte, pth string, visiting map[string]bool) error {
+	if visiting[pth] {
+		return fmt.Errorf("include cycle found: %s", pth)
+	}
+	visiting[pth] = true
+	defer delete(visiting, pth)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#include") {
+			if err := c.include(line, pth, visiting); err != nil {
+				return fmt.Errorf("%s:%d: %s", pth, lineNumber, err)
+			}
+			continue
+		}
+
+		assignmentIdx := assignmentIndex(line)
+		if assignmentIdx == -1 {
+			return fmt.Errorf("%s:%d: invalid line: %s", pth, lineNumber, line)
+		}
+
+		key, conditions := ParseKey(line[:assignmentIdx])
+		if key == "" {
+			return fmt.Errorf("%s:%d: missing build setting name: %s", pth, lineNumber, line)
+		}
+
+		c.Assignments = append(c.Assignments, Assignment{
+			Key:        key,
+			Conditions: conditions,
+			Value:      strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line[assignmentIdx+1:]), ";")),
+			Path:       pth,
+			Line:       lineNumber,
+		})
+	}
+	return scanner.Err()
+}
+
+func (c *Config) include(line, pth string, visiting map[string]bool) error {
+	optional := strings.HasPrefix(line, "#include?")
+	includePth := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "#include?"), "#include"))
+	if len(includePth) < 2 || !strings.HasPrefix(includePth, `"`) || !strings.HasSuffix(includePth, `"`) {
+		return fmt.Errorf("invalid include directive: %s", line)
+	}
+	includePth = strings.Trim(includePth, `"`)
+	if !filepath.IsAbs(includePth) {
+		includePth = filepath.Join(filepath.Dir(pth), includePth)
+	}
+
+	if _, err := os.Stat(includePth); os.IsNotExist(err) && optional {
+		return nil
+	}
+
+	content, err := fileutil.ReadBytesFromFile(includePth)
+	if err != nil {
+		return err
+	}
+
+	c.Includes = append(c.Includes, includePth)
+	return c.parse(content, includePth, visiting)
+}
+
+// assignmentIndex returns the index of the assignment operator,
+// the conditions of the key may contain '=' too: KEY[sdk=iphoneos*] = VALUE
+func assignmentIndex(line string) int {
+	depth := 0
+	for i, c := range line {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '=':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Object returns the merged build settings of the file.
+// The conditional settings are kept with their conditions in the key (like: KEY[sdk=iphoneos*]).
+// A repeated assignment overrides the previous one, its $(inherited) references are replaced by the previous value.
+// The $(inherited) references without a previous value are kept, those refer to the lower levels (like the project's build settings).
+func (c Config) Object() serialized.Object {
+	settings := serialized.Object{}
+	for _, assignment := range c.Assignments {
+		key := assignment.FullKey()
+		settings[key] = chainInherited(assignment.Value, settings[key])
+	}
+	return settings
+}
+
+// Evaluate returns the merged build settings matching the given condition values (like: {"sdk": "iphoneos", "config": "Debug"}).
+// The matching conditional settings override the unconditional ones, the more specific conditions win.
+func (c Config) Evaluate(values map[string]string) serialized.Object {
+	type candidate struct {
+		conditions Conditions
+		value      string
+	}
+
+	candidatesByKey := map[string][]candidate{}
+	for key, value := range c.Object() {
+		name, conditions := ParseKey(key)
+		if !conditions.Match(values) {
+			continue
+		}
+		candidatesByKey[name] = append(candidatesByKey[name], candidate{conditions: conditions, value: value.(string)})
+	}
+
+	settings := serialized.Object{}
+	for name, candidates := range candidatesByKey {
+		sort.Slice(candidates, func(i, j int) bool {
+			if len(candidates[i].conditions) == len(candidates[j].conditions) {
+				return candidates[i].conditions.String() < candidates[j].conditions.String()
+			}
+			return len(candidates[i].conditions) < len(candidates[j].conditions)
+		})
+		settings[name] = candidates[len(candidates)-1].value
+	}
+	return settings
+}
+
+func chainInherited(value string, previous interface{}) string {
+	previousValue, ok := previous.(string)
+	if !ok {
+		return value
+	}
+	return strings.NewReplacer("$(inherited)", previousValue, "${inherited}", previousValue).Replace(value)
+}
@@ -0,0 +1,114 @@
+package xcconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	testhelper.CreateFileInDir(t, dir, "Shared/Base.xcconfig", `// Base settings
+PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.app
+OTHER_LDFLAGS = -ObjC
+`)
+	pth := testhelper.CreateFileInDir(t, dir, "App.xcconfig", `#include "Shared/Base.xcconfig"
+#include? "Local.xcconfig"
+
+OTHER_LDFLAGS = $(inherited) -lz // link zlib
+CODE_SIGN_IDENTITY[sdk=iphoneos*] = iPhone Distribution;
+CODE_SIGN_IDENTITY[sdk=iphoneos*][arch=arm64] = Apple Distribution
+CODE_SIGN_IDENTITY = Apple Development
+SWIFT_FLAGS = $(inherited) -DAPP
+`)
+
+	config, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, pth, config.Path)
+	require.Equal(t, []string{filepath.Join(dir, "Shared/Base.xcconfig")}, config.Includes)
+	require.Equal(t, 7, len(config.Assignments))
+	require.Equal(t, Assignment{
+		Key:        "CODE_SIGN_IDENTITY",
+		Conditions: Conditions{{Name: "sdk", Pattern: "iphoneos*"}, {Name: "arch", Pattern: "arm64"}},
+		Value:      "Apple Distribution",
+		Path:       pth,
+		Line:       6,
+	}, config.Assignments[4])
+
+	require.Equal(t, serialized.Object{
+		"PRODUCT_BUNDLE_IDENTIFIER":                     "com.bitrise.app",
+		"OTHER_LDFLAGS":                                 "-ObjC -lz",
+		"CODE_SIGN_IDENTITY[sdk=iphoneos*]":             "iPhone Distribution",
+		"CODE_SIGN_IDENTITY[sdk=iphoneos*][arch=arm64]": "Apple Distribution",
+		"CODE_SIGN_IDENTITY":                            "Apple Development",
+		"SWIFT_FLAGS":                                   "$(inherited) -DAPP",
+	}, config.Object())
+
+	t.Log("evaluate conditions")
+	{
+		settings := config.Evaluate(map[string]string{"sdk": "iphoneos14.5"})
+		ensureValue(t, settings, "CODE_SIGN_IDENTITY", "iPhone Distribution")
+
+		settings = config.Evaluate(map[string]string{"sdk": "iphoneos14.5", "arch": "arm64"})
+		ensureValue(t, settings, "CODE_SIGN_IDENTITY", "Apple Distribution")
+
+		settings = config.Evaluate(map[string]string{"sdk": "iphonesimulator14.5"})
+		ensureValue(t, settings, "CODE_SIGN_IDENTITY", "Apple Development")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	{
+		_, err := Parse([]byte("KEY VALUE"), filepath.Join(dir, "Invalid.xcconfig"))
+		require.EqualError(t, err, filepath.Join(dir, "Invalid.xcconfig")+":1: invalid line: KEY VALUE")
+	}
+
+	{
+		_, err := Parse([]byte(`#include "Missing.xcconfig"`), filepath.Join(dir, "Include.xcconfig"))
+		require.Error(t, err)
+	}
+
+	{
+		pth := testhelper.CreateFileInDir(t, dir, "Cycle.xcconfig", `#include "Cycle.xcconfig"`)
+		_, err := Open(pth)
+		require.EqualError(t, err, pth+":1: include cycle found: "+pth)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key            string
+		wantName       string
+		wantConditions Conditions
+	}{
+		{key: "KEY", wantName: "KEY"},
+		{key: "KEY[sdk=iphoneos*]", wantName: "KEY", wantConditions: Conditions{{Name: "sdk", Pattern: "iphoneos*"}}},
+		{key: "KEY[sdk=iphoneos*][arch=arm64] ", wantName: "KEY", wantConditions: Conditions{{Name: "sdk", Pattern: "iphoneos*"}, {Name: "arch", Pattern: "arm64"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			name, conditions := ParseKey(tt.key)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, tt.wantConditions, conditions)
+		})
+	}
+}
+
+func TestConditions_Match(t *testing.T) {
+	conditions := Conditions{{Name: "sdk", Pattern: "iphoneos*"}, {Name: "arch", Pattern: "arm64"}}
+	require.True(t, conditions.Match(map[string]string{"sdk": "iphoneos", "arch": "arm64"}))
+	require.False(t, conditions.Match(map[string]string{"sdk": "iphoneos"}))
+	require.False(t, conditions.Match(map[string]string{"sdk": "macosx", "arch": "arm64"}))
+	require.True(t, Conditions(nil).Match(nil))
+}
+
+func ensureValue(t *testing.T, obj serialized.Object, key, value string) {
+	v, err := obj.String(key)
+	require.NoError(t, err)
+	require.Equal(t, value, v)
+}
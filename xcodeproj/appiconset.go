@@ -74,6 +74,9 @@ func lookupAppIconPaths(projectPath string, assetCatalogs []fileReference, appIc
 			return nil, err
 		} else if resolvedPath == "" {
 			return nil, fmt.Errorf("could not resolve path")
+		} else if strings.HasPrefix(resolvedPath, "$(") {
+			// the asset catalog is only known at build time (like a generated one in DERIVED_FILE_DIR)
+			continue
 		}
 
 		re := regexp.MustCompile(`\$\{(.+)\}`)
@@ -126,14 +129,14 @@ func filterAssetCatalogs(buildPhase resourcesBuildPhase, projectID string, objec
 	assetCatalogs := []fileReference{}
 	for _, fileUUID := range buildPhase.files {
 		buildFile, err := parseBuildFile(fileUUID, objects)
-		if err != nil {
+		if err != nil || buildFile.FileRef == "" {
 			// ignore:
 			// D0177B971F26869C0044446D /* (null) in Resources */ = {isa = PBXBuildFile; };
 			continue
 		}
 
 		// can be PBXVariantGroup or PBXFileReference
-		rawElement, err := objects.Object(buildFile.fileRef)
+		rawElement, err := objects.Object(buildFile.FileRef)
 		if err != nil {
 			return nil, err
 		}
@@ -144,7 +147,7 @@ func filterAssetCatalogs(buildPhase resourcesBuildPhase, projectID string, objec
 			continue
 		}
 
-		fileReference, err := parseFileReference(buildFile.fileRef, objects)
+		fileReference, err := parseFileReference(buildFile.FileRef, objects)
 		if err != nil {
 			return nil, err
 		}
@@ -1,6 +1,9 @@
 package xcodeproj
 
-import "github.com/bitrise-io/xcode-project/serialized"
+import (
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcconfig"
+)
 
 // BuildConfiguration ..
 type BuildConfiguration struct {
@@ -31,3 +34,46 @@ func parseBuildConfiguration(id string, objects serialized.Object) (BuildConfigu
 		BuildSettings: buildSettings,
 	}, nil
 }
+
+// BaseConfigurationPath returns the absolute path of the xcconfig file the build configuration is based on
+// (referenced by the build configuration's baseConfigurationReference).
+// Empty path is returned if the build configuration is not based on an xcconfig file.
+func (p XcodeProj) BaseConfigurationPath(buildConfiguration BuildConfiguration) (string, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+
+	rawBuildConfiguration, err := objects.Object(buildConfiguration.ID)
+	if err != nil {
+		return "", err
+	}
+
+	fileRefID, err := rawBuildConfiguration.String("baseConfigurationReference")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return resolveObjectAbsolutePath(fileRefID, p.Proj.ID, p.Path, objects)
+}
+
+// BaseConfiguration returns the parsed xcconfig file the build configuration is based on.
+// nil is returned if the build configuration is not based on an xcconfig file.
+func (p XcodeProj) BaseConfiguration(buildConfiguration BuildConfiguration) (*xcconfig.Config, error) {
+	pth, err := p.BaseConfigurationPath(buildConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	if pth == "" {
+		return nil, nil
+	}
+
+	config, err := xcconfig.Open(pth)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
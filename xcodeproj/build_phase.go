@@ -0,0 +1,232 @@
+package xcodeproj
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// BuildPhaseType ...
+type BuildPhaseType string
+
+// BuildPhaseTypes
+const (
+	SourcesBuildPhaseType     BuildPhaseType = "PBXSourcesBuildPhase"
+	FrameworksBuildPhaseType  BuildPhaseType = "PBXFrameworksBuildPhase"
+	HeadersBuildPhaseType     BuildPhaseType = "PBXHeadersBuildPhase"
+	ResourcesBuildPhaseType   BuildPhaseType = "PBXResourcesBuildPhase"
+	CopyFilesBuildPhaseType   BuildPhaseType = "PBXCopyFilesBuildPhase"
+	ShellScriptBuildPhaseType BuildPhaseType = "PBXShellScriptBuildPhase"
+	RezBuildPhaseType         BuildPhaseType = "PBXRezBuildPhase"
+)
+
+// DstSubfolderSpec is the destination of a PBXCopyFilesBuildPhase.
+type DstSubfolderSpec int
+
+// DstSubfolderSpecs
+const (
+	AbsolutePathDstSubfolderSpec      DstSubfolderSpec = 0
+	WrapperDstSubfolderSpec           DstSubfolderSpec = 1
+	ExecutablesDstSubfolderSpec       DstSubfolderSpec = 6
+	ResourcesDstSubfolderSpec         DstSubfolderSpec = 7
+	FrameworksDstSubfolderSpec        DstSubfolderSpec = 10
+	SharedFrameworksDstSubfolderSpec  DstSubfolderSpec = 11
+	SharedSupportDstSubfolderSpec     DstSubfolderSpec = 12
+	PlugInsDstSubfolderSpec           DstSubfolderSpec = 13
+	JavaResourcesDstSubfolderSpec     DstSubfolderSpec = 15
+	ProductsDirectoryDstSubfolderSpec DstSubfolderSpec = 16
+)
+
+// BuildPhase represents a build phase element of a target, like a PBXSourcesBuildPhase or a PBXCopyFilesBuildPhase.
+type BuildPhase struct {
+	Type  BuildPhaseType
+	ID    string
+	Name  string
+	Files []BuildFile
+
+	RunOnlyForDeploymentPostprocessing bool
+
+	// PBXCopyFilesBuildPhase properties
+	DstPath          string
+	DstSubfolderSpec DstSubfolderSpec
+
+	// PBXShellScriptBuildPhase properties
+	ShellPath           string
+	ShellScript         string
+	InputPaths          []string
+	OutputPaths         []string
+	InputFileListPaths  []string
+	OutputFileListPaths []string
+	ShowEnvVarsInLog    bool
+	AlwaysOutOfDate     bool
+}
+
+// BuildFile represents a PBXBuildFile element
+// 47C11A4A21FF63970084FD7F /* Assets.xcassets in Resources */ = {isa = PBXBuildFile; fileRef = 47C11A4921FF63970084FD7F /* Assets.xcassets */; };
+type BuildFile struct {
+	ID string
+	// FileRef is the ID of the referenced file tree element (PBXFileReference, PBXVariantGroup, ...).
+	FileRef string
+	// ProductRef is the ID of the referenced Swift package product (XCSwiftPackageProductDependency).
+	ProductRef string
+	// Attributes lists the ATTRIBUTES settings, like: CodeSignOnCopy, RemoveHeadersOnCopy, Weak, Public or Private.
+	Attributes    []string
+	CompilerFlags string
+}
+
+// HasAttribute reports whether the build file has the given attribute, like: CodeSignOnCopy.
+func (f BuildFile) HasAttribute(attribute string) bool {
+	for _, a := range f.Attributes {
+		if a == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+// RawObject ...
+func (f BuildFile) RawObject() (map[string]interface{}, error) {
+	raw := map[string]interface{}{"isa": "PBXBuildFile"}
+	if f.FileRef != "" {
+		raw["fileRef"] = f.FileRef
+	}
+	if f.ProductRef != "" {
+		raw["productRef"] = f.ProductRef
+	}
+
+	settings := map[string]interface{}{}
+	if len(f.Attributes) > 0 {
+		settings["ATTRIBUTES"] = rawStringSlice(f.Attributes)
+	}
+	if f.CompilerFlags != "" {
+		settings["COMPILER_FLAGS"] = f.CompilerFlags
+	}
+	if len(settings) > 0 {
+		raw["settings"] = settings
+	}
+
+	return raw, nil
+}
+
+func parseBuildPhase(id string, objects serialized.Object) (BuildPhase, error) {
+	rawBuildPhase, err := objects.Object(id)
+	if err != nil {
+		return BuildPhase{}, err
+	}
+
+	isa, err := rawBuildPhase.String("isa")
+	if err != nil {
+		return BuildPhase{}, err
+	}
+
+	buildPhase := BuildPhase{
+		Type:             BuildPhaseType(isa),
+		ID:               id,
+		ShowEnvVarsInLog: true,
+	}
+
+	stringProperties := map[string]*string{
+		"name":        &buildPhase.Name,
+		"dstPath":     &buildPhase.DstPath,
+		"shellPath":   &buildPhase.ShellPath,
+		"shellScript": &buildPhase.ShellScript,
+	}
+	for key, value := range stringProperties {
+		if *value, err = rawBuildPhase.String(key); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return BuildPhase{}, err
+		}
+	}
+
+	stringSliceProperties := map[string]*[]string{
+		"inputPaths":          &buildPhase.InputPaths,
+		"outputPaths":         &buildPhase.OutputPaths,
+		"inputFileListPaths":  &buildPhase.InputFileListPaths,
+		"outputFileListPaths": &buildPhase.OutputFileListPaths,
+	}
+	for key, value := range stringSliceProperties {
+		if *value, err = rawBuildPhase.StringSlice(key); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return BuildPhase{}, err
+		}
+	}
+
+	boolProperties := map[string]*bool{
+		"runOnlyForDeploymentPostprocessing": &buildPhase.RunOnlyForDeploymentPostprocessing,
+		"showEnvVarsInLog":                   &buildPhase.ShowEnvVarsInLog,
+		"alwaysOutOfDate":                    &buildPhase.AlwaysOutOfDate,
+	}
+	for key, value := range boolProperties {
+		raw, err := rawBuildPhase.String(key)
+		if err != nil {
+			if serialized.IsKeyNotFoundError(err) {
+				continue
+			}
+			return BuildPhase{}, err
+		}
+		*value = raw == "1"
+	}
+
+	if dstSubfolderSpec, err := rawBuildPhase.String("dstSubfolderSpec"); err == nil {
+		var spec int
+		if _, err := fmt.Sscanf(dstSubfolderSpec, "%d", &spec); err != nil {
+			return BuildPhase{}, fmt.Errorf("invalid dstSubfolderSpec (%s): %s", dstSubfolderSpec, err)
+		}
+		buildPhase.DstSubfolderSpec = DstSubfolderSpec(spec)
+	} else if !serialized.IsKeyNotFoundError(err) {
+		return BuildPhase{}, err
+	}
+
+	fileIDs, err := rawBuildPhase.StringSlice("files")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return BuildPhase{}, err
+	}
+	for _, fileID := range fileIDs {
+		file, err := parseBuildFile(fileID, objects)
+		if err != nil {
+			// dangling or malformed build file reference
+			if !serialized.IsKeyNotFoundError(err) {
+				log.Warnf("skipping build file (%s) of build phase (%s): %s", fileID, id, err)
+			}
+			continue
+		}
+		buildPhase.Files = append(buildPhase.Files, file)
+	}
+
+	return buildPhase, nil
+}
+
+func parseBuildFile(id string, objects serialized.Object) (BuildFile, error) {
+	rawBuildFile, err := objects.Object(id)
+	if err != nil {
+		return BuildFile{}, err
+	}
+	if isa, err := rawBuildFile.String("isa"); err != nil {
+		return BuildFile{}, err
+	} else if isa != "PBXBuildFile" {
+		return BuildFile{}, fmt.Errorf("not a PBXBuildFile element")
+	}
+
+	buildFile := BuildFile{ID: id}
+	for key, value := range map[string]*string{"fileRef": &buildFile.FileRef, "productRef": &buildFile.ProductRef} {
+		if *value, err = rawBuildFile.String(key); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return BuildFile{}, err
+		}
+	}
+
+	settings, err := rawBuildFile.Object("settings")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return buildFile, nil
+		}
+		return BuildFile{}, err
+	}
+
+	if buildFile.Attributes, err = settings.StringSlice("ATTRIBUTES"); err != nil && !serialized.IsKeyNotFoundError(err) {
+		return BuildFile{}, err
+	}
+	if buildFile.CompilerFlags, err = settings.String("COMPILER_FLAGS"); err != nil && !serialized.IsKeyNotFoundError(err) {
+		return BuildFile{}, err
+	}
+
+	return buildFile, nil
+}
@@ -0,0 +1,200 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarget_BuildPhases(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+
+	want := []BuildPhase{
+		{
+			Type: SourcesBuildPhaseType,
+			ID:   "7D5B35F820E28EE80022BAE6",
+			Files: []BuildFile{
+				{ID: "7D5B360220E28EE80022BAE6", FileRef: "7D5B360120E28EE80022BAE6"},
+				{ID: "7D5B360020E28EE80022BAE6", FileRef: "7D5B35FF20E28EE80022BAE6"},
+			},
+			ShowEnvVarsInLog: true,
+		},
+		{
+			Type:             FrameworksBuildPhaseType,
+			ID:               "7D5B35F920E28EE80022BAE6",
+			ShowEnvVarsInLog: true,
+		},
+		{
+			Type: ResourcesBuildPhaseType,
+			ID:   "7D5B35FA20E28EE80022BAE6",
+			Files: []BuildFile{
+				{ID: "7D5B360A20E28EEA0022BAE6", FileRef: "7D5B360820E28EEA0022BAE6"},
+				{ID: "7D5B360720E28EEA0022BAE6", FileRef: "7D5B360620E28EEA0022BAE6"},
+				{ID: "7D5B360520E28EE80022BAE6", FileRef: "7D5B360320E28EE80022BAE6"},
+			},
+			ShowEnvVarsInLog: true,
+		},
+		{
+			Type: CopyFilesBuildPhaseType,
+			ID:   "7D03431E20F4BB070050B6A6",
+			Name: "Embed App Extensions",
+			Files: []BuildFile{
+				{ID: "7D03431A20F4BB070050B6A6", FileRef: "7D03430D20F4BB070050B6A6", Attributes: []string{"RemoveHeadersOnCopy"}},
+			},
+			DstSubfolderSpec: PlugInsDstSubfolderSpec,
+			ShowEnvVarsInLog: true,
+		},
+	}
+	require.Equal(t, want, target.BuildPhases())
+}
+
+func TestOpen_MalformedBuildPhases(t *testing.T) {
+	content := testhelper.XcodeProjectTest
+	// the Embed App Extensions phase has an invalid dstSubfolderSpec
+	content = strings.Replace(content, "dstSubfolderSpec = 13;", "dstSubfolderSpec = plugins;", 1)
+	// a Sources phase entry references a file reference, instead of a build file
+	content = strings.Replace(content, "7D5B360020E28EE80022BAE6 /* AppDelegate.swift in Sources */,", "7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */,", 1)
+	pbxProjPth := testhelper.CreateFileInDir(t, t.TempDir(), "XcodeProj.xcodeproj/project.pbxproj", content)
+
+	project, err := Open(filepath.Dir(pbxProjPth))
+	require.NoError(t, err)
+
+	target, ok := project.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+
+	var types []BuildPhaseType
+	for _, buildPhase := range target.BuildPhases() {
+		types = append(types, buildPhase.Type)
+	}
+	require.Equal(t, []BuildPhaseType{SourcesBuildPhaseType, FrameworksBuildPhaseType, ResourcesBuildPhaseType}, types)
+	require.Equal(t, []BuildFile{
+		{ID: "7D5B360220E28EE80022BAE6", FileRef: "7D5B360120E28EE80022BAE6"},
+	}, target.BuildPhases()[0].Files)
+}
+
+func Test_parseBuildPhase(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawBuildPhases), &raw)
+	require.NoError(t, err)
+
+	t.Log("headers build phase")
+	{
+		buildPhase, err := parseBuildPhase("13B07F861A680F5B00A75B9A", raw)
+		require.NoError(t, err)
+		require.Equal(t, HeadersBuildPhaseType, buildPhase.Type)
+		require.Equal(t, 2, len(buildPhase.Files))
+		require.True(t, buildPhase.Files[0].HasAttribute("Public"))
+		require.True(t, buildPhase.Files[1].HasAttribute("Private"))
+		require.False(t, buildPhase.Files[1].HasAttribute("Public"))
+	}
+
+	t.Log("copy files build phase")
+	{
+		buildPhase, err := parseBuildPhase("13B07F871A680F5B00A75B9A", raw)
+		require.NoError(t, err)
+		require.Equal(t, CopyFilesBuildPhaseType, buildPhase.Type)
+		require.Equal(t, "Embed Frameworks", buildPhase.Name)
+		require.Equal(t, FrameworksDstSubfolderSpec, buildPhase.DstSubfolderSpec)
+		require.Equal(t, []BuildFile{
+			{ID: "13B07FC11A68108700A75B9A", FileRef: "13B07FB01A68108700A75B9A", Attributes: []string{"CodeSignOnCopy", "RemoveHeadersOnCopy"}},
+		}, buildPhase.Files)
+	}
+
+	t.Log("shell script build phase")
+	{
+		buildPhase, err := parseBuildPhase("13B07F881A680F5B00A75B9A", raw)
+		require.NoError(t, err)
+		require.Equal(t, BuildPhase{
+			Type:                               ShellScriptBuildPhaseType,
+			ID:                                 "13B07F881A680F5B00A75B9A",
+			Name:                               "Run Script",
+			RunOnlyForDeploymentPostprocessing: true,
+			ShellPath:                          "/bin/sh",
+			ShellScript:                        "echo \"Hello\"\n",
+			InputPaths:                         []string{"$(SRCROOT)/input.txt"},
+			OutputPaths:                        []string{},
+			InputFileListPaths:                 []string{},
+			OutputFileListPaths:                []string{"$(SRCROOT)/outputs.xcfilelist"},
+			ShowEnvVarsInLog:                   false,
+			AlwaysOutOfDate:                    true,
+		}, buildPhase)
+	}
+
+	t.Log("sources build phase with compiler flags and a dangling build file")
+	{
+		buildPhase, err := parseBuildPhase("13B07F891A680F5B00A75B9A", raw)
+		require.NoError(t, err)
+		require.Equal(t, []BuildFile{
+			{ID: "13B07FC31A68108700A75B9A", FileRef: "13B07FB21A68108700A75B9A", CompilerFlags: "-fno-objc-arc"},
+		}, buildPhase.Files)
+	}
+}
+
+const rawBuildPhases = `
+{
+		13B07FC01A68108700A75B9A /* Lib.h in Headers */ = {isa = PBXBuildFile; fileRef = 13B07FAF1A68108700A75B9A /* Lib.h */; settings = {ATTRIBUTES = (Public, ); }; };
+		13B07FBF1A68108700A75B9A /* Lib+Private.h in Headers */ = {isa = PBXBuildFile; fileRef = 13B07FAE1A68108700A75B9A /* Lib+Private.h */; settings = {ATTRIBUTES = (Private, ); }; };
+		13B07FC11A68108700A75B9A /* Lib.framework in Embed Frameworks */ = {isa = PBXBuildFile; fileRef = 13B07FB01A68108700A75B9A /* Lib.framework */; settings = {ATTRIBUTES = (CodeSignOnCopy, RemoveHeadersOnCopy, ); }; };
+		13B07FC31A68108700A75B9A /* main.m in Sources */ = {isa = PBXBuildFile; fileRef = 13B07FB21A68108700A75B9A /* main.m */; settings = {COMPILER_FLAGS = "-fno-objc-arc"; }; };
+		13B07F861A680F5B00A75B9A /* Headers */ = {
+			isa = PBXHeadersBuildPhase;
+			buildActionMask = 2147483647;
+			files = (
+				13B07FC01A68108700A75B9A /* Lib.h in Headers */,
+				13B07FBF1A68108700A75B9A /* Lib+Private.h in Headers */,
+			);
+			runOnlyForDeploymentPostprocessing = 0;
+		};
+		13B07F871A680F5B00A75B9A /* Embed Frameworks */ = {
+			isa = PBXCopyFilesBuildPhase;
+			buildActionMask = 2147483647;
+			dstPath = "";
+			dstSubfolderSpec = 10;
+			files = (
+				13B07FC11A68108700A75B9A /* Lib.framework in Embed Frameworks */,
+			);
+			name = "Embed Frameworks";
+			runOnlyForDeploymentPostprocessing = 0;
+		};
+		13B07F881A680F5B00A75B9A /* Run Script */ = {
+			isa = PBXShellScriptBuildPhase;
+			alwaysOutOfDate = 1;
+			buildActionMask = 2147483647;
+			files = (
+			);
+			inputFileListPaths = (
+			);
+			inputPaths = (
+				"$(SRCROOT)/input.txt",
+			);
+			name = "Run Script";
+			outputFileListPaths = (
+				"$(SRCROOT)/outputs.xcfilelist",
+			);
+			outputPaths = (
+			);
+			runOnlyForDeploymentPostprocessing = 1;
+			shellPath = /bin/sh;
+			shellScript = "echo \"Hello\"\n";
+			showEnvVarsInLog = 0;
+		};
+		13B07F891A680F5B00A75B9A /* Sources */ = {
+			isa = PBXSourcesBuildPhase;
+			buildActionMask = 2147483647;
+			files = (
+				13B07FC31A68108700A75B9A /* main.m in Sources */,
+				13B07FC41A68108700A75B9A /* missing.m in Sources */,
+			);
+			runOnlyForDeploymentPostprocessing = 0;
+		};
+}
+`
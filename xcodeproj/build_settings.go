@@ -0,0 +1,360 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcconfig"
+)
+
+// BuildSettingsBackend selects how the effective build settings of a target are computed.
+type BuildSettingsBackend string
+
+// BuildSettingsBackends
+const (
+	// XcodebuildBuildSettingsBackend runs `xcodebuild -showBuildSettings`, it requires Xcode to be installed.
+	// This is the default backend.
+	XcodebuildBuildSettingsBackend BuildSettingsBackend = "xcodebuild"
+	// OfflineBuildSettingsBackend computes the build settings in-process from the project file and its xcconfig files.
+	OfflineBuildSettingsBackend BuildSettingsBackend = "offline"
+)
+
+const defaultDeveloperDir = "/Applications/Xcode.app/Contents/Developer"
+
+// OfflineTargetBuildSettings returns the effective build settings of the given target and configuration
+// without calling xcodebuild.
+//
+// The settings are layered the same way as Xcode does (from the lowest to the highest precedence):
+// built-in defaults, the project level xcconfig, the project level build settings,
+// the target level xcconfig, the target level build settings and the command line overrides.
+// `$(inherited)` refers to the value of the setting on the lower levels.
+//
+// Supported customOptions: `-sdk <sdk name>`, `-arch <arch>` and `KEY=VALUE` build setting overrides.
+// If configuration is empty, the target's default configuration is used.
+func (p XcodeProj) OfflineTargetBuildSettings(target, configuration string, customOptions ...string) (serialized.Object, error) {
+	t, ok := p.Proj.TargetByName(target)
+	if !ok {
+		return nil, fmt.Errorf("could not find target (%s)", target)
+	}
+
+	if configuration == "" {
+		configuration = t.BuildConfigurationList.DefaultConfigurationName
+	}
+
+	options, err := parseOfflineBuildSettingsOptions(customOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	targetConfiguration, ok := t.BuildConfigurationList.BuildConfiguration(configuration)
+	if !ok {
+		return nil, fmt.Errorf("could not find configuration (%s) for target (%s)", configuration, target)
+	}
+
+	var projectConfigurationLayers []serialized.Object
+	if projectConfiguration, ok := p.Proj.BuildConfigurationList.BuildConfiguration(configuration); ok {
+		projectConfigurationLayers, err = p.buildConfigurationLayers(projectConfiguration)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	targetConfigurationLayers, err := p.buildConfigurationLayers(targetConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := p.defaultBuildSettings(t, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []serialized.Object{defaults}
+	layers = append(layers, projectConfigurationLayers...)
+	layers = append(layers, targetConfigurationLayers...)
+	layers = append(layers, options.overrides)
+
+	conditions := map[string]string{"config": configuration, "arch": options.arch}
+
+	// The SDK needs to be known to evaluate the sdk conditional build settings and the platform specific defaults.
+	sdk := options.sdk
+	if sdk == "" {
+		sdk, err = newBuildSettingsStack(layers, conditions).evaluate("SDKROOT")
+		if err != nil {
+			return nil, err
+		}
+	}
+	conditions["sdk"] = sdk
+
+	for key, value := range platformBuildSettings(sdk) {
+		defaults[key] = value
+	}
+
+	return newBuildSettingsStack(layers, conditions).evaluateAll()
+}
+
+// buildConfigurationLayers returns the build settings of the given build configuration
+// preceded by the xcconfig file's settings it is based on.
+func (p XcodeProj) buildConfigurationLayers(buildConfiguration BuildConfiguration) ([]serialized.Object, error) {
+	baseConfiguration, err := p.BaseConfiguration(buildConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base configuration of %s: %s", buildConfiguration.Name, err)
+	}
+	if baseConfiguration == nil {
+		return []serialized.Object{buildConfiguration.BuildSettings}, nil
+	}
+
+	return []serialized.Object{baseConfiguration.Object(), buildConfiguration.BuildSettings}, nil
+}
+
+func (p XcodeProj) defaultBuildSettings(target Target, configuration string) (serialized.Object, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	rawProject, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir := filepath.Dir(p.Path)
+	if projectDirPath, err := rawProject.String("projectDirPath"); err == nil && projectDirPath != "" {
+		projectDir = filepath.Join(projectDir, projectDirPath)
+	}
+
+	developmentRegion, err := rawProject.String("developmentRegion")
+	if err != nil {
+		developmentRegion = "en"
+	}
+
+	productName := target.Name
+	if rawTarget, err := objects.Object(target.ID); err == nil {
+		if name, err := rawTarget.String("productName"); err == nil && name != "" {
+			productName = name
+		}
+	}
+
+	developerDir := os.Getenv("DEVELOPER_DIR")
+	if developerDir == "" {
+		developerDir = defaultDeveloperDir
+	}
+
+	defaults := serialized.Object{
+		"ACTION":                  "build",
+		"CONFIGURATION":           configuration,
+		"DEVELOPER_DIR":           developerDir,
+		"DEVELOPMENT_LANGUAGE":    developmentRegion,
+		"PROJECT":                 p.Name,
+		"PROJECT_NAME":            p.Name,
+		"PROJECT_FILE_PATH":       p.Path,
+		"PROJECT_DIR":             projectDir,
+		"SRCROOT":                 "$(PROJECT_DIR)",
+		"SOURCE_ROOT":             "$(PROJECT_DIR)",
+		"SDKROOT":                 "macosx",
+		"TARGET_NAME":             target.Name,
+		"TARGETNAME":              "$(TARGET_NAME)",
+		"PRODUCT_NAME":            productName,
+		"PRODUCT_TYPE":            target.ProductType,
+		"EXECUTABLE_NAME":         "$(PRODUCT_NAME)",
+		"SYMROOT":                 "$(PROJECT_DIR)/build",
+		"OBJROOT":                 "$(SYMROOT)",
+		"BUILD_ROOT":              "$(SYMROOT)",
+		"BUILD_DIR":               "$(SYMROOT)",
+		"CONFIGURATION_BUILD_DIR": "$(BUILD_DIR)/$(CONFIGURATION)$(EFFECTIVE_PLATFORM_NAME)",
+		"BUILT_PRODUCTS_DIR":      "$(CONFIGURATION_BUILD_DIR)",
+		"TARGET_BUILD_DIR":        "$(CONFIGURATION_BUILD_DIR)",
+	}
+
+	if wrapperExtension := productTypeWrapperExtension(target.ProductType); wrapperExtension != "" {
+		defaults["WRAPPER_EXTENSION"] = wrapperExtension
+		defaults["WRAPPER_NAME"] = "$(PRODUCT_NAME).$(WRAPPER_EXTENSION)"
+		defaults["FULL_PRODUCT_NAME"] = "$(WRAPPER_NAME)"
+	} else {
+		defaults["FULL_PRODUCT_NAME"] = "$(EXECUTABLE_NAME)"
+	}
+
+	return defaults, nil
+}
+
+func productTypeWrapperExtension(productType string) string {
+	switch productType {
+	case "com.apple.product-type.application", "com.apple.product-type.application.watchapp2", "com.apple.product-type.application.messages":
+		return "app"
+	case "com.apple.product-type.app-extension", "com.apple.product-type.watchkit2-extension", "com.apple.product-type.app-extension.messages", "com.apple.product-type.app-extension.messages-sticker-pack", "com.apple.product-type.extensionkit-extension":
+		return "appex"
+	case "com.apple.product-type.bundle.unit-test", "com.apple.product-type.bundle.ui-testing":
+		return "xctest"
+	case "com.apple.product-type.framework":
+		return "framework"
+	case "com.apple.product-type.bundle":
+		return "bundle"
+	default:
+		return ""
+	}
+}
+
+// platformBuildSettings returns the defaults depending on the sdk (for example: iphoneos, iphonesimulator14.5, macosx).
+func platformBuildSettings(sdk string) serialized.Object {
+	platform := strings.TrimRight(sdk, "0123456789.")
+
+	effectivePlatformName := ""
+	if platform != "macosx" {
+		effectivePlatformName = "-" + platform
+	}
+
+	settings := serialized.Object{
+		"SDK_NAME":                sdk,
+		"PLATFORM_NAME":           platform,
+		"EFFECTIVE_PLATFORM_NAME": effectivePlatformName,
+	}
+
+	if platform == "macosx" {
+		settings["CONTENTS_FOLDER_PATH"] = "$(WRAPPER_NAME)/Contents"
+		settings["INFOPLIST_PATH"] = "$(CONTENTS_FOLDER_PATH)/Info.plist"
+	} else {
+		settings["CONTENTS_FOLDER_PATH"] = "$(WRAPPER_NAME)"
+		settings["INFOPLIST_PATH"] = "$(CONTENTS_FOLDER_PATH)/Info.plist"
+	}
+
+	return settings
+}
+
+type offlineBuildSettingsOptions struct {
+	sdk       string
+	arch      string
+	overrides serialized.Object
+}
+
+func parseOfflineBuildSettingsOptions(customOptions []string) (offlineBuildSettingsOptions, error) {
+	options := offlineBuildSettingsOptions{overrides: serialized.Object{}}
+	for i := 0; i < len(customOptions); i++ {
+		option := customOptions[i]
+		switch {
+		case option == "-sdk" || option == "-arch":
+			if i+1 >= len(customOptions) {
+				return offlineBuildSettingsOptions{}, fmt.Errorf("missing value for option: %s", option)
+			}
+			i++
+			if option == "-sdk" {
+				options.sdk = customOptions[i]
+			} else {
+				options.arch = customOptions[i]
+			}
+		case !strings.HasPrefix(option, "-") && strings.Contains(option, "="):
+			split := strings.SplitN(option, "=", 2)
+			options.overrides[split[0]] = split[1]
+		default:
+			return offlineBuildSettingsOptions{}, fmt.Errorf("unsupported option for offline build settings: %s", option)
+		}
+	}
+	return options, nil
+}
+
+// buildSettingsStack is a stack of build setting layers, the last layer has the highest precedence.
+type buildSettingsStack struct {
+	layers []serialized.Object
+}
+
+func newBuildSettingsStack(layers []serialized.Object, conditions map[string]string) buildSettingsStack {
+	var resolvedLayers []serialized.Object
+	for _, layer := range layers {
+		resolvedLayers = append(resolvedLayers, resolveConditionalKeys(layer, conditions))
+	}
+	return buildSettingsStack{layers: resolvedLayers}
+}
+
+// resolveConditionalKeys drops the conditional settings not matching the conditions,
+// the matching ones override the unconditional setting with the same name.
+// Like xcconfig.Config.Evaluate, the settings with more conditions win.
+func resolveConditionalKeys(layer serialized.Object, conditions map[string]string) serialized.Object {
+	type candidate struct {
+		key        string
+		conditions xcconfig.Conditions
+	}
+
+	candidatesByName := map[string][]candidate{}
+	for key := range layer {
+		name, keyConditions := xcconfig.ParseKey(key)
+		if !keyConditions.Match(conditions) {
+			continue
+		}
+		candidatesByName[name] = append(candidatesByName[name], candidate{key: key, conditions: keyConditions})
+	}
+
+	resolved := serialized.Object{}
+	for name, candidates := range candidatesByName {
+		sort.Slice(candidates, func(i, j int) bool {
+			if len(candidates[i].conditions) == len(candidates[j].conditions) {
+				return candidates[i].conditions.String() < candidates[j].conditions.String()
+			}
+			return len(candidates[i].conditions) < len(candidates[j].conditions)
+		})
+		resolved[name] = layer[candidates[len(candidates)-1].key]
+	}
+
+	return resolved
+}
+
+func (s buildSettingsStack) evaluateAll() (serialized.Object, error) {
+	settings := serialized.Object{}
+	for _, layer := range s.layers {
+		for key := range layer {
+			if _, ok := settings[key]; ok {
+				continue
+			}
+
+			value, err := s.evaluate(key)
+			if err != nil {
+				return nil, err
+			}
+			settings[key] = value
+		}
+	}
+	return settings, nil
+}
+
+func (s buildSettingsStack) evaluate(key string) (string, error) {
+	return s.evaluateAt(key, len(s.layers)-1, nil)
+}
+
+// evaluateAt evaluates the key using the layers up to the given level,
+// chain lists the settings being evaluated (to report reference cycles).
+func (s buildSettingsStack) evaluateAt(key string, level int, chain []string) (string, error) {
+	for ; level >= 0; level-- {
+		if _, ok := s.layers[level][key]; ok {
+			break
+		}
+	}
+	if level < 0 {
+		return "", nil
+	}
+
+	visitingKey := fmt.Sprintf("%s@%d", key, level)
+	for i, visited := range chain {
+		if visited == visitingKey {
+			var cycle []string
+			for _, k := range append(chain[i:], visitingKey) {
+				cycle = append(cycle, strings.Split(k, "@")[0])
+			}
+			return "", BuildSettingCycleError{Chain: cycle}
+		}
+	}
+	chain = append(chain[:len(chain):len(chain)], visitingKey)
+
+	// $(inherited) and the self references are evaluated on the levels below the key's level
+	resolve := func(name string) (string, error) {
+		if name == "inherited" || name == key {
+			return s.evaluateAt(key, level-1, chain)
+		}
+		return s.evaluateAt(name, len(s.layers)-1, chain)
+	}
+
+	return expandMacroValue(s.layers[level][key], func(value string) (string, error) {
+		return expandMacros(value, resolve)
+	})
+}
@@ -0,0 +1,194 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_OfflineTargetBuildSettings(t *testing.T) {
+	projectDir := t.TempDir()
+	pbxProjPth := testhelper.CreateFileInDir(t, projectDir, "XcodeProj.xcodeproj/project.pbxproj", testhelper.XcodeProjectTest)
+	testhelper.CreateFileInDir(t, projectDir, "Config/Base.xcconfig", `// Base configuration
+BUNDLE_ID_PREFIX = com.bitrise
+OTHER_LDFLAGS = -ObjC
+`)
+	testhelper.CreateFileInDir(t, projectDir, "Config/App.xcconfig", `#include "Base.xcconfig"
+#include? "Missing.xcconfig"
+OTHER_LDFLAGS = $(inherited) -lz // chained
+CODE_SIGN_IDENTITY[sdk=iphoneos*] = iPhone Distribution
+`)
+
+	project, err := Open(filepath.Dir(pbxProjPth))
+	require.NoError(t, err)
+
+	// base the XcodeProj target's Debug configuration on Config/App.xcconfig
+	objects, err := project.RawProj.Object("objects")
+	require.NoError(t, err)
+	objects["AAAAAAAAAAAAAAAAAAAAAAAA"] = map[string]interface{}{
+		"isa":               "PBXFileReference",
+		"lastKnownFileType": "text.xcconfig",
+		"path":              "Config/App.xcconfig",
+		"sourceTree":        "<group>",
+	}
+	mainGroup, err := objects.Object("7D5B35F320E28EE80022BAE6")
+	require.NoError(t, err)
+	mainGroup["children"] = append(mainGroup["children"].([]interface{}), "AAAAAAAAAAAAAAAAAAAAAAAA")
+	targetDebugConfiguration, err := objects.Object("7D5B360F20E28EEA0022BAE6")
+	require.NoError(t, err)
+	targetDebugConfiguration["baseConfigurationReference"] = "AAAAAAAAAAAAAAAAAAAAAAAA"
+	targetDebugBuildSettings, err := targetDebugConfiguration.Object("buildSettings")
+	require.NoError(t, err)
+	targetDebugBuildSettings["PRODUCT_BUNDLE_IDENTIFIER"] = "$(BUNDLE_ID_PREFIX).$(TARGET_NAME)"
+
+	t.Log("base configuration")
+	{
+		target := findTarget(t, &project, "XcodeProj")
+		baseConfiguration, err := project.BaseConfiguration(findBuildConfiguration(t, target, "Debug"))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(projectDir, "Config/App.xcconfig"), baseConfiguration.Path)
+
+		baseConfiguration, err = project.BaseConfiguration(findBuildConfiguration(t, target, "Release"))
+		require.NoError(t, err)
+		require.Nil(t, baseConfiguration)
+	}
+
+	project.BuildSettingsBackend = OfflineBuildSettingsBackend
+
+	settings, err := project.TargetBuildSettings("XcodeProj", "Debug")
+	require.NoError(t, err)
+
+	for key, want := range map[string]string{
+		"PRODUCT_BUNDLE_IDENTIFIER":    "com.bitrise.XcodeProj",
+		"PRODUCT_NAME":                 "XcodeProj",
+		"TARGET_NAME":                  "XcodeProj",
+		"CONFIGURATION":                "Debug",
+		"SRCROOT":                      projectDir,
+		"SDKROOT":                      "iphoneos",
+		"PLATFORM_NAME":                "iphoneos",
+		"INFOPLIST_FILE":               "XcodeProj/Info.plist",
+		"INFOPLIST_PATH":               "XcodeProj.app/Info.plist",
+		"BUILT_PRODUCTS_DIR":           filepath.Join(projectDir, "build") + "/Debug-iphoneos",
+		"OTHER_LDFLAGS":                "-ObjC -lz",
+		"CODE_SIGN_IDENTITY":           "iPhone Distribution",
+		"LD_RUNPATH_SEARCH_PATHS":      "@executable_path/Frameworks",
+		"GCC_PREPROCESSOR_DEFINITIONS": "DEBUG=1",
+	} {
+		got, err := settings.String(key)
+		require.NoError(t, err, key)
+		require.Equal(t, want, got, key)
+	}
+
+	t.Log("sdk and build setting overrides")
+	{
+		settings, err := project.OfflineTargetBuildSettings("XcodeProj", "Debug", "-sdk", "iphonesimulator", "PRODUCT_NAME=Overridden")
+		require.NoError(t, err)
+		ensureValue(t, settings, "CODE_SIGN_IDENTITY", "iPhone Developer")
+		ensureValue(t, settings, "EFFECTIVE_PLATFORM_NAME", "-iphonesimulator")
+		ensureValue(t, settings, "INFOPLIST_PATH", "Overridden.app/Info.plist")
+	}
+
+	t.Log("bundle id")
+	{
+		bundleID, err := project.TargetBundleID("XcodeProj", "Debug")
+		require.NoError(t, err)
+		require.Equal(t, "com.bitrise.XcodeProj", bundleID)
+	}
+
+	t.Log("unknown configuration")
+	{
+		_, err := project.OfflineTargetBuildSettings("XcodeProj", "Staging")
+		require.EqualError(t, err, "could not find configuration (Staging) for target (XcodeProj)")
+	}
+
+	t.Log("unsupported option")
+	{
+		_, err := project.OfflineTargetBuildSettings("XcodeProj", "Debug", "-destination", "generic/platform=iOS")
+		require.EqualError(t, err, "unsupported option for offline build settings: -destination")
+	}
+}
+
+func Test_buildSettingsStack_evaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layers  []serialized.Object
+		key     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "inherited",
+			layers: []serialized.Object{
+				{"FLAGS": "-a"},
+				{"FLAGS": "$(inherited) -b"},
+				{"FLAGS": []interface{}{"$(inherited)", "-c"}},
+			},
+			key:  "FLAGS",
+			want: "-a -b -c",
+		},
+		{
+			name: "self reference is inherited",
+			layers: []serialized.Object{
+				{"FLAGS": "-a"},
+				{"FLAGS": "$(FLAGS) -b"},
+			},
+			key:  "FLAGS",
+			want: "-a -b",
+		},
+		{
+			name: "nested reference",
+			layers: []serialized.Object{
+				{"NAME_Debug": "debug", "CONFIGURATION": "Debug", "NAME": "$(NAME_$(CONFIGURATION))"},
+			},
+			key:  "NAME",
+			want: "debug",
+		},
+		{
+			name: "undefined reference",
+			layers: []serialized.Object{
+				{"NAME": "prefix$(UNDEFINED).${UNDEFINED}"},
+			},
+			key:  "NAME",
+			want: "prefix.",
+		},
+		{
+			name: "cycle",
+			layers: []serialized.Object{
+				{"A": "$(B)", "B": "$(A)"},
+			},
+			key:     "A",
+			wantErr: "build setting reference cycle found: A -> B -> A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newBuildSettingsStack(tt.layers, map[string]string{}).evaluate(tt.key)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_resolveConditionalKeys(t *testing.T) {
+	layer := serialized.Object{
+		"IDENTITY":                          "Apple Development",
+		"IDENTITY[sdk=iphonesimulator*]":    "-",
+		"IDENTITY[sdk=*][arch=*]":           "Apple Distribution",
+		"FLAGS[arch=arm64]":                 "-arm64",
+		"FLAGS[sdk=iphoneos*]":              "-iphoneos",
+		"OTHER[config=Release][sdk=macosx]": "-release",
+	}
+
+	resolved := resolveConditionalKeys(layer, map[string]string{"sdk": "iphonesimulator14.5", "arch": "arm64", "config": "Debug"})
+	require.Equal(t, serialized.Object{
+		"IDENTITY": "Apple Distribution",
+		"FLAGS":    "-arm64",
+	}, resolved)
+}
@@ -46,6 +46,16 @@ func parseConfigurationList(id string, objects serialized.Object) (Configuration
 	}, nil
 }
 
+// BuildConfiguration returns the list's build configuration with the given name.
+func (l ConfigurationList) BuildConfiguration(name string) (BuildConfiguration, bool) {
+	for _, buildConfiguration := range l.BuildConfigurations {
+		if buildConfiguration.Name == name {
+			return buildConfiguration, true
+		}
+	}
+	return BuildConfiguration{}, false
+}
+
 // BuildConfigurationList ...
 func (p XcodeProj) BuildConfigurationList(targetID string) (serialized.Object, error) {
 	objects, err := p.RawProj.Object("objects")
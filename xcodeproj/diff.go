@@ -0,0 +1,382 @@
+package xcodeproj
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ProjectChangeType is the kind of the project element a change belongs to.
+type ProjectChangeType string
+
+// ProjectChangeTypes
+const (
+	TargetProjectChangeType              ProjectChangeType = "target"
+	BuildConfigurationProjectChangeType  ProjectChangeType = "build-configuration"
+	BuildSettingProjectChangeType        ProjectChangeType = "build-setting"
+	TargetFileProjectChangeType          ProjectChangeType = "target-file"
+	SwiftPackageProjectChangeType        ProjectChangeType = "swift-package"
+	SwiftPackageProductProjectChangeType ProjectChangeType = "swift-package-product"
+)
+
+// ProjectChangeKind ...
+type ProjectChangeKind string
+
+// ProjectChangeKinds
+const (
+	AddedProjectChangeKind    ProjectChangeKind = "added"
+	RemovedProjectChangeKind  ProjectChangeKind = "removed"
+	ModifiedProjectChangeKind ProjectChangeKind = "modified"
+)
+
+// ProjectChange is a semantic change between two versions of a project.
+type ProjectChange struct {
+	Type ProjectChangeType `json:"type"`
+	Kind ProjectChangeKind `json:"kind"`
+	// Target is the name of the changed target (the new name if the target is renamed), empty for the project level changes.
+	Target string `json:"target,omitempty"`
+	// Configuration is the name of the build configuration of the build setting changes.
+	Configuration string `json:"configuration,omitempty"`
+	// BuildPhase is the name (or type if unnamed) of the build phase of the target file changes.
+	BuildPhase string `json:"build_phase,omitempty"`
+	// Key identifies the changed element: the name of the target, the build configuration, the build setting
+	// or the Swift package product, the path of the file in the project navigator or the Swift package's repository URL (or path).
+	Key string `json:"key"`
+	// Old and New are the values before and after the change, like the build setting values or the package requirements.
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// String returns the change as a single line, without its target.
+func (c ProjectChange) String() string {
+	sign := map[ProjectChangeKind]string{
+		AddedProjectChangeKind:    "+",
+		RemovedProjectChangeKind:  "-",
+		ModifiedProjectChangeKind: "~",
+	}[c.Kind]
+
+	subject := strings.Replace(string(c.Type), "-", " ", -1) + " " + c.Key
+	switch c.Type {
+	case BuildSettingProjectChangeType:
+		subject = fmt.Sprintf("[%s] %s", c.Configuration, c.Key)
+	case TargetFileProjectChangeType:
+		subject = fmt.Sprintf("file %s (%s)", c.Key, c.BuildPhase)
+	}
+
+	switch {
+	case c.Old != nil && c.New != nil:
+		return fmt.Sprintf("%s %s: %s -> %s", sign, subject, formatChangeValue(c.Old), formatChangeValue(c.New))
+	case c.New != nil:
+		return fmt.Sprintf("%s %s: %s", sign, subject, formatChangeValue(c.New))
+	case c.Old != nil:
+		return fmt.Sprintf("%s %s: %s", sign, subject, formatChangeValue(c.Old))
+	default:
+		return fmt.Sprintf("%s %s", sign, subject)
+	}
+}
+
+func formatChangeValue(value interface{}) string {
+	if values, ok := value.([]interface{}); ok {
+		var elements []string
+		for _, element := range values {
+			elements = append(elements, fmt.Sprintf("%v", element))
+		}
+		return "(" + strings.Join(elements, ", ") + ")"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// ProjectDiff is the list of semantic changes between two versions of a project,
+// the project level changes come first, followed by the changes of the targets.
+type ProjectDiff struct {
+	Changes []ProjectChange `json:"changes"`
+}
+
+// Text renders the changes grouped by target.
+func (d ProjectDiff) Text() string {
+	var groups []string
+	changesByGroup := map[string][]ProjectChange{}
+	for _, change := range d.Changes {
+		if _, ok := changesByGroup[change.Target]; !ok {
+			groups = append(groups, change.Target)
+		}
+		changesByGroup[change.Target] = append(changesByGroup[change.Target], change)
+	}
+
+	var b strings.Builder
+	for _, group := range groups {
+		if group == "" {
+			b.WriteString("Project:\n")
+		} else {
+			b.WriteString(fmt.Sprintf("Target %s:\n", group))
+		}
+		for _, change := range changesByGroup[group] {
+			b.WriteString("  " + change.String() + "\n")
+		}
+	}
+	return b.String()
+}
+
+// JSON renders the changes as a JSON document.
+func (d ProjectDiff) JSON() ([]byte, error) {
+	if d.Changes == nil {
+		d.Changes = []ProjectChange{}
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// String ...
+func (r SwiftPackageRequirement) String() string {
+	switch r.Kind {
+	case UpToNextMajorVersionRequirementKind, UpToNextMinorVersionRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.MinimumVersion)
+	case VersionRangeRequirementKind:
+		return fmt.Sprintf("%s %s..<%s", r.Kind, r.MinimumVersion, r.MaximumVersion)
+	case ExactVersionRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.Version)
+	case BranchRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.Branch)
+	case RevisionRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.Revision)
+	default:
+		return string(r.Kind)
+	}
+}
+
+// DiffProjects returns the semantic changes from the old to the new version of the project:
+// the targets added, removed or renamed, the build configurations added or removed, the build settings changed per configuration,
+// the files added to or removed from the targets' build phases and the Swift packages and package products added, removed or bumped.
+// The targets are matched by ID, the build configurations by name.
+func DiffProjects(oldProj, newProj XcodeProj) ProjectDiff {
+	var changes []ProjectChange
+
+	changes = append(changes, diffSwiftPackages(oldProj.Proj.SwiftPackageReferences(), newProj.Proj.SwiftPackageReferences())...)
+	changes = append(changes, diffConfigurationLists("", oldProj.Proj.BuildConfigurationList, newProj.Proj.BuildConfigurationList)...)
+
+	oldFilePaths := fileElementDisplayPaths(oldProj)
+	newFilePaths := fileElementDisplayPaths(newProj)
+
+	for _, newTarget := range newProj.Proj.Targets {
+		oldTarget, ok := oldProj.Proj.Target(newTarget.ID)
+		if !ok {
+			changes = append(changes, ProjectChange{Type: TargetProjectChangeType, Kind: AddedProjectChangeKind, Target: newTarget.Name, Key: newTarget.Name})
+			continue
+		}
+
+		if oldTarget.Name != newTarget.Name {
+			changes = append(changes, ProjectChange{Type: TargetProjectChangeType, Kind: ModifiedProjectChangeKind, Target: newTarget.Name, Key: newTarget.Name, Old: oldTarget.Name, New: newTarget.Name})
+		}
+		changes = append(changes, diffConfigurationLists(newTarget.Name, oldTarget.BuildConfigurationList, newTarget.BuildConfigurationList)...)
+		changes = append(changes, diffTargetFiles(newTarget.Name, targetFiles(oldTarget, oldFilePaths), targetFiles(newTarget, newFilePaths))...)
+		changes = append(changes, diffSwiftPackageProducts(newTarget.Name, oldTarget.SwiftPackageProductDependencies(), newTarget.SwiftPackageProductDependencies())...)
+	}
+
+	for _, oldTarget := range oldProj.Proj.Targets {
+		if _, ok := newProj.Proj.Target(oldTarget.ID); !ok {
+			changes = append(changes, ProjectChange{Type: TargetProjectChangeType, Kind: RemovedProjectChangeKind, Target: oldTarget.Name, Key: oldTarget.Name})
+		}
+	}
+
+	return ProjectDiff{Changes: changes}
+}
+
+// DiffPBXProj returns the semantic changes between the old and the new project.pbxproj contents (see DiffProjects).
+func DiffPBXProj(oldContent, newContent []byte) (ProjectDiff, error) {
+	oldProj, err := parsePBXProjContent(oldContent)
+	if err != nil {
+		return ProjectDiff{}, fmt.Errorf("failed to parse old project: %s", err)
+	}
+	newProj, err := parsePBXProjContent(newContent)
+	if err != nil {
+		return ProjectDiff{}, fmt.Errorf("failed to parse new project: %s", err)
+	}
+	return DiffProjects(*oldProj, *newProj), nil
+}
+
+func diffConfigurationLists(targetName string, oldList, newList ConfigurationList) []ProjectChange {
+	var changes []ProjectChange
+	for _, newConfiguration := range newList.BuildConfigurations {
+		oldConfiguration, ok := oldList.BuildConfiguration(newConfiguration.Name)
+		if !ok {
+			changes = append(changes, ProjectChange{Type: BuildConfigurationProjectChangeType, Kind: AddedProjectChangeKind, Target: targetName, Configuration: newConfiguration.Name, Key: newConfiguration.Name})
+			continue
+		}
+
+		keys := map[string]bool{}
+		for key := range oldConfiguration.BuildSettings {
+			keys[key] = true
+		}
+		for key := range newConfiguration.BuildSettings {
+			keys[key] = true
+		}
+		var sortedKeys []string
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, key := range sortedKeys {
+			oldValue, inOld := oldConfiguration.BuildSettings[key]
+			newValue, inNew := newConfiguration.BuildSettings[key]
+			change := ProjectChange{Type: BuildSettingProjectChangeType, Target: targetName, Configuration: newConfiguration.Name, Key: key, Old: oldValue, New: newValue}
+			switch {
+			case !inOld:
+				change.Kind = AddedProjectChangeKind
+			case !inNew:
+				change.Kind = RemovedProjectChangeKind
+			case !reflect.DeepEqual(oldValue, newValue):
+				change.Kind = ModifiedProjectChangeKind
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	for _, oldConfiguration := range oldList.BuildConfigurations {
+		if _, ok := newList.BuildConfiguration(oldConfiguration.Name); !ok {
+			changes = append(changes, ProjectChange{Type: BuildConfigurationProjectChangeType, Kind: RemovedProjectChangeKind, Target: targetName, Configuration: oldConfiguration.Name, Key: oldConfiguration.Name})
+		}
+	}
+	return changes
+}
+
+// targetFile is a file tree element in a build phase of a target.
+type targetFile struct {
+	buildPhase string
+	fileRef    string
+	path       string
+}
+
+// targetFiles returns the files of the target's build phases, the Swift package products are skipped (see diffSwiftPackageProducts).
+func targetFiles(target Target, filePaths map[string]string) []targetFile {
+	var files []targetFile
+	for _, buildPhase := range target.BuildPhases() {
+		name := buildPhase.Name
+		if name == "" {
+			name = strings.TrimSuffix(strings.TrimPrefix(string(buildPhase.Type), "PBX"), "BuildPhase")
+		}
+		for _, buildFile := range buildPhase.Files {
+			if buildFile.FileRef == "" {
+				continue
+			}
+			pth, ok := filePaths[buildFile.FileRef]
+			if !ok {
+				pth = buildFile.FileRef
+			}
+			files = append(files, targetFile{buildPhase: name, fileRef: buildFile.FileRef, path: pth})
+		}
+	}
+	return files
+}
+
+func diffTargetFiles(targetName string, oldFiles, newFiles []targetFile) []ProjectChange {
+	contains := func(files []targetFile, file targetFile) bool {
+		for _, f := range files {
+			if f.buildPhase == file.buildPhase && f.fileRef == file.fileRef {
+				return true
+			}
+		}
+		return false
+	}
+
+	var changes []ProjectChange
+	for _, file := range newFiles {
+		if !contains(oldFiles, file) {
+			changes = append(changes, ProjectChange{Type: TargetFileProjectChangeType, Kind: AddedProjectChangeKind, Target: targetName, BuildPhase: file.buildPhase, Key: file.path})
+		}
+	}
+	for _, file := range oldFiles {
+		if !contains(newFiles, file) {
+			changes = append(changes, ProjectChange{Type: TargetFileProjectChangeType, Kind: RemovedProjectChangeKind, Target: targetName, BuildPhase: file.buildPhase, Key: file.path})
+		}
+	}
+	return changes
+}
+
+// fileElementDisplayPaths maps the file tree element IDs to their path in Xcode's project navigator, like: App/Sources/AppDelegate.swift.
+// It returns an empty map if the file tree can not be parsed.
+func fileElementDisplayPaths(p XcodeProj) map[string]string {
+	paths := map[string]string{}
+	mainGroup, err := p.MainGroup()
+	if err != nil {
+		return paths
+	}
+
+	_ = mainGroup.Walk(func(element FileElement) error {
+		var components []string
+		for e := element; e != nil && e.Base().Parent() != nil; e = e.Base().Parent() {
+			components = append([]string{e.Base().DisplayName()}, components...)
+		}
+		paths[element.Base().ID] = strings.Join(components, "/")
+		return nil
+	})
+	return paths
+}
+
+func swiftPackageKey(reference SwiftPackageReference) string {
+	if reference.Type == LocalSwiftPackageReferenceType {
+		return reference.RelativePath
+	}
+	return reference.RepositoryURL
+}
+
+func diffSwiftPackages(oldReferences, newReferences []SwiftPackageReference) []ProjectChange {
+	find := func(references []SwiftPackageReference, key string) (SwiftPackageReference, bool) {
+		for _, reference := range references {
+			if swiftPackageKey(reference) == key {
+				return reference, true
+			}
+		}
+		return SwiftPackageReference{}, false
+	}
+
+	var changes []ProjectChange
+	for _, newReference := range newReferences {
+		key := swiftPackageKey(newReference)
+		oldReference, ok := find(oldReferences, key)
+		switch {
+		case !ok:
+			change := ProjectChange{Type: SwiftPackageProjectChangeType, Kind: AddedProjectChangeKind, Key: key}
+			if newReference.Type == RemoteSwiftPackageReferenceType {
+				change.New = newReference.Requirement.String()
+			}
+			changes = append(changes, change)
+		case oldReference.Requirement != newReference.Requirement:
+			changes = append(changes, ProjectChange{Type: SwiftPackageProjectChangeType, Kind: ModifiedProjectChangeKind, Key: key, Old: oldReference.Requirement.String(), New: newReference.Requirement.String()})
+		}
+	}
+	for _, oldReference := range oldReferences {
+		key := swiftPackageKey(oldReference)
+		if _, ok := find(newReferences, key); !ok {
+			changes = append(changes, ProjectChange{Type: SwiftPackageProjectChangeType, Kind: RemovedProjectChangeKind, Key: key})
+		}
+	}
+	return changes
+}
+
+func diffSwiftPackageProducts(targetName string, oldDependencies, newDependencies []SwiftPackageProductDependency) []ProjectChange {
+	contains := func(dependencies []SwiftPackageProductDependency, productName string) bool {
+		for _, dependency := range dependencies {
+			if dependency.ProductName == productName {
+				return true
+			}
+		}
+		return false
+	}
+
+	var changes []ProjectChange
+	for _, dependency := range newDependencies {
+		if !contains(oldDependencies, dependency.ProductName) {
+			changes = append(changes, ProjectChange{Type: SwiftPackageProductProjectChangeType, Kind: AddedProjectChangeKind, Target: targetName, Key: dependency.ProductName})
+		}
+	}
+	for _, dependency := range oldDependencies {
+		if !contains(newDependencies, dependency.ProductName) {
+			changes = append(changes, ProjectChange{Type: SwiftPackageProductProjectChangeType, Kind: RemovedProjectChangeKind, Target: targetName, Key: dependency.ProductName})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,109 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPBXProj(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+	proj.IDGenerator = NewSeededObjectIDGenerator(1)
+
+	packageID, err := proj.AddRemoteSwiftPackage("https://github.com/Alamofire/Alamofire", SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.0.0"})
+	require.NoError(t, err)
+	oldContent, err := proj.perObjectModify()
+	require.NoError(t, err)
+
+	proj, err = parsePBXProjContent(oldContent)
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+	proj.IDGenerator = NewSeededObjectIDGenerator(2)
+
+	require.NoError(t, proj.UpdateSwiftPackageRequirement(packageID, SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.4.0"}))
+	_, err = proj.AddLocalSwiftPackage("Packages/Core")
+	require.NoError(t, err)
+	_, err = proj.AddSwiftPackageProduct("XcodeProj", packageID, "Alamofire")
+	require.NoError(t, err)
+
+	fileID, err := proj.AddFileReference("7D5B35FE20E28EE80022BAE6", FileReference{FileElementBase: FileElementBase{Path: "Generated.swift"}})
+	require.NoError(t, err)
+	_, err = proj.AddFileToTargets(fileID, SourcesBuildPhaseType, "XcodeProj")
+	require.NoError(t, err)
+
+	require.NoError(t, proj.RemoveTarget("XcodeProjUITests", false))
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	rawTarget, err := objects.Object("7D03430C20F4BB070050B6A6")
+	require.NoError(t, err)
+	rawTarget["name"] = "Widget"
+
+	projectDebug, ok := proj.Proj.BuildConfigurationList.BuildConfiguration("Debug")
+	require.True(t, ok)
+	rawBuildConfiguration, err := objects.Object(projectDebug.ID)
+	require.NoError(t, err)
+	buildSettings, err := rawBuildConfiguration.Object("buildSettings")
+	require.NoError(t, err)
+	buildSettings["SWIFT_STRICT_CONCURRENCY"] = "complete"
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	targetRelease, ok := target.BuildConfigurationList.BuildConfiguration("Release")
+	require.True(t, ok)
+	rawBuildConfiguration, err = objects.Object(targetRelease.ID)
+	require.NoError(t, err)
+	buildSettings, err = rawBuildConfiguration.Object("buildSettings")
+	require.NoError(t, err)
+	buildSettings["PRODUCT_BUNDLE_IDENTIFIER"] = "com.bitrise.App"
+
+	newContent, err := proj.perObjectModify()
+	require.NoError(t, err)
+
+	diff, err := DiffPBXProj(oldContent, newContent)
+	require.NoError(t, err)
+	require.Equal(t, []ProjectChange{
+		{Type: SwiftPackageProjectChangeType, Kind: ModifiedProjectChangeKind, Key: "https://github.com/Alamofire/Alamofire", Old: "upToNextMajorVersion 5.0.0", New: "upToNextMajorVersion 5.4.0"},
+		{Type: SwiftPackageProjectChangeType, Kind: AddedProjectChangeKind, Key: "Packages/Core"},
+		{Type: BuildSettingProjectChangeType, Kind: AddedProjectChangeKind, Configuration: "Debug", Key: "SWIFT_STRICT_CONCURRENCY", New: "complete"},
+		{Type: BuildSettingProjectChangeType, Kind: ModifiedProjectChangeKind, Target: "XcodeProj", Configuration: "Release", Key: "PRODUCT_BUNDLE_IDENTIFIER", Old: "com.bitrise.XcodeProj", New: "com.bitrise.App"},
+		{Type: TargetFileProjectChangeType, Kind: AddedProjectChangeKind, Target: "XcodeProj", BuildPhase: "Sources", Key: "XcodeProj/Generated.swift"},
+		{Type: SwiftPackageProductProjectChangeType, Kind: AddedProjectChangeKind, Target: "XcodeProj", Key: "Alamofire"},
+		{Type: TargetProjectChangeType, Kind: ModifiedProjectChangeKind, Target: "Widget", Key: "Widget", Old: "TodayExtension", New: "Widget"},
+		{Type: TargetProjectChangeType, Kind: RemovedProjectChangeKind, Target: "XcodeProjUITests", Key: "XcodeProjUITests"},
+	}, diff.Changes)
+
+	require.Equal(t, `Project:
+  ~ swift package https://github.com/Alamofire/Alamofire: upToNextMajorVersion 5.0.0 -> upToNextMajorVersion 5.4.0
+  + swift package Packages/Core
+  + [Debug] SWIFT_STRICT_CONCURRENCY: complete
+Target XcodeProj:
+  ~ [Release] PRODUCT_BUNDLE_IDENTIFIER: com.bitrise.XcodeProj -> com.bitrise.App
+  + file XcodeProj/Generated.swift (Sources)
+  + swift package product Alamofire
+Target Widget:
+  ~ target Widget: TodayExtension -> Widget
+Target XcodeProjUITests:
+  - target XcodeProjUITests
+`, diff.Text())
+
+	json, err := diff.JSON()
+	require.NoError(t, err)
+	require.Contains(t, string(json), `"type": "build-setting",
+      "kind": "modified",
+      "target": "XcodeProj",
+      "configuration": "Release",
+      "key": "PRODUCT_BUNDLE_IDENTIFIER",
+      "old": "com.bitrise.XcodeProj",
+      "new": "com.bitrise.App"`)
+
+	diff, err = DiffPBXProj(newContent, newContent)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(diff.Changes))
+	json, err = diff.JSON()
+	require.NoError(t, err)
+	require.Equal(t, "{\n  \"changes\": []\n}", string(json))
+}
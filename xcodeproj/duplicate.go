@@ -0,0 +1,372 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// DuplicateBuildConfiguration clones the build configuration named from as a new configuration named to,
+// in the project's and every target's configuration list, which contains the from configuration.
+func (p *XcodeProj) DuplicateBuildConfiguration(from, to string) error {
+	if to == "" {
+		return fmt.Errorf("empty build configuration name")
+	}
+	if _, ok := p.Proj.BuildConfigurationList.BuildConfiguration(from); !ok {
+		return fmt.Errorf("failed to find build configuration with name: %s", from)
+	}
+
+	configurationLists := []ConfigurationList{p.Proj.BuildConfigurationList}
+	for _, target := range p.Proj.Targets {
+		configurationLists = append(configurationLists, target.BuildConfigurationList)
+	}
+	for _, configurationList := range configurationLists {
+		if _, ok := configurationList.BuildConfiguration(to); ok {
+			return fmt.Errorf("build configuration (%s) already exists in configuration list: %s", to, configurationList.ID)
+		}
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+
+	for _, configurationList := range configurationLists {
+		source, ok := configurationList.BuildConfiguration(from)
+		if !ok {
+			continue
+		}
+
+		rawSource, err := objects.Object(source.ID)
+		if err != nil {
+			return err
+		}
+		rawConfiguration := deepCopy(map[string]interface{}(rawSource)).(map[string]interface{})
+		rawConfiguration["name"] = to
+
+		id, err := p.NewObjectID()
+		if err != nil {
+			return err
+		}
+		objects[id] = rawConfiguration
+
+		rawConfigurationList, err := objects.Object(configurationList.ID)
+		if err != nil {
+			return err
+		}
+		configurationIDs, err := rawConfigurationList.StringSlice("buildConfigurations")
+		if err != nil {
+			return err
+		}
+		rawConfigurationList["buildConfigurations"] = rawStringSlice(append(configurationIDs, id))
+	}
+
+	return p.reloadProj()
+}
+
+// DuplicateSchemesForBuildConfiguration copies the project's schemes using the from build configuration in any of their actions,
+// the copies use the to configuration instead. The copy of the "App" scheme is saved as "App <to>" next to the original scheme on Save.
+// The paths of the new schemes are returned.
+func (p *XcodeProj) DuplicateSchemesForBuildConfiguration(from, to string) ([]string, error) {
+	schemes, err := p.Schemes()
+	if err != nil {
+		return nil, err
+	}
+
+	var schemeCopies []xcscheme.Scheme
+	for _, scheme := range schemes {
+		schemeCopy := scheme
+		referencesFrom := false
+		for _, buildConfiguration := range []*string{
+			&schemeCopy.TestAction.BuildConfiguration,
+			&schemeCopy.LaunchAction.BuildConfiguration,
+			&schemeCopy.ProfileAction.BuildConfiguration,
+			&schemeCopy.AnalyzeAction.BuildConfiguration,
+			&schemeCopy.ArchiveAction.BuildConfiguration,
+		} {
+			if *buildConfiguration == from {
+				*buildConfiguration = to
+				referencesFrom = true
+			}
+		}
+		if !referencesFrom {
+			continue
+		}
+
+		schemeCopy.Name = fmt.Sprintf("%s %s", scheme.Name, to)
+		schemeCopy.Path = filepath.Join(filepath.Dir(scheme.Path), schemeCopy.Name+".xcscheme")
+		if exist, err := pathutil.IsPathExists(schemeCopy.Path); err != nil {
+			return nil, err
+		} else if exist {
+			return nil, fmt.Errorf("scheme already exists: %s", schemeCopy.Path)
+		}
+		schemeCopies = append(schemeCopies, schemeCopy)
+	}
+
+	var pths []string
+	for _, schemeCopy := range schemeCopies {
+		p.saveScheme(schemeCopy)
+		pths = append(pths, schemeCopy.Path)
+	}
+	return pths, nil
+}
+
+// DuplicateTarget deep-copies the target as a new target named newName and returns the new target's ID.
+// The copy gets fresh IDs for the target, its build phases and build files, build rules, dependencies,
+// Swift package product dependencies, configuration list and build configurations, and gets its own product reference.
+// Every build configuration of the copy sets the PRODUCT_BUNDLE_IDENTIFIER to bundleID.
+// The build files of the copy reference the same files as the original target's.
+func (p *XcodeProj) DuplicateTarget(targetName, newName, bundleID string) (string, error) {
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return "", fmt.Errorf("failed to find target with name: %s", targetName)
+	}
+	if newName == "" {
+		return "", fmt.Errorf("empty target name")
+	}
+	if _, ok := p.Proj.TargetByName(newName); ok {
+		return "", fmt.Errorf("target already exists with name: %s", newName)
+	}
+	if bundleID == "" {
+		return "", fmt.Errorf("empty bundle ID")
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+	rawTarget, err := objects.Object(target.ID)
+	if err != nil {
+		return "", err
+	}
+
+	ids, err := ownedTargetObjectIDs(target, rawTarget, objects)
+	if err != nil {
+		return "", err
+	}
+
+	newIDs := map[string]string{}
+	for _, id := range ids {
+		if newIDs[id], err = p.NewObjectID(); err != nil {
+			return "", err
+		}
+	}
+	for _, id := range ids {
+		object, err := objects.Object(id)
+		if err != nil {
+			return "", err
+		}
+		objects[newIDs[id]] = remapIDs(map[string]interface{}(object), newIDs)
+	}
+
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		newBuildConfiguration, err := objects.Object(newIDs[buildConfiguration.ID])
+		if err != nil {
+			return "", err
+		}
+		buildSettings, err := newBuildConfiguration.Object("buildSettings")
+		if err != nil {
+			if !serialized.IsKeyNotFoundError(err) {
+				return "", err
+			}
+			buildSettings = map[string]interface{}{}
+			newBuildConfiguration["buildSettings"] = map[string]interface{}(buildSettings)
+		}
+		buildSettings["PRODUCT_BUNDLE_IDENTIFIER"] = bundleID
+	}
+
+	newTargetID := newIDs[target.ID]
+	newRawTarget, err := objects.Object(newTargetID)
+	if err != nil {
+		return "", err
+	}
+	newRawTarget["name"] = newName
+	if _, ok := newRawTarget["productName"]; ok {
+		newRawTarget["productName"] = newName
+	}
+
+	if productReferenceID, err := newRawTarget.String("productReference"); err == nil {
+		productReference, err := objects.Object(productReferenceID)
+		if err != nil {
+			return "", err
+		}
+		if pth, err := productReference.String("path"); err == nil {
+			productReference["path"] = newName + path.Ext(pth)
+		}
+		delete(productReference, "name")
+
+		if err := p.addProductReference(productReference, productReferenceID); err != nil {
+			return "", err
+		}
+	}
+
+	project, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return "", err
+	}
+	targetIDs, err := project.StringSlice("targets")
+	if err != nil {
+		return "", err
+	}
+	project["targets"] = rawStringSlice(append(targetIDs, newTargetID))
+
+	if targetAttributes, err := p.Proj.Attributes.TargetAttributes.Object(target.ID); err == nil {
+		rawTargetAttributes, err := rawTargetAttributes(project)
+		if err != nil {
+			return "", err
+		}
+		rawTargetAttributes[newTargetID] = deepCopy(map[string]interface{}(targetAttributes))
+	}
+
+	return newTargetID, p.reloadProj()
+}
+
+// ownedTargetObjectIDs returns the unique IDs of the target and the objects belonging only to the target.
+func ownedTargetObjectIDs(target Target, rawTarget serialized.Object, objects serialized.Object) ([]string, error) {
+	ids := []string{target.ID}
+	for _, key := range []string{"buildPhases", "buildRules", "dependencies", "packageProductDependencies"} {
+		owned, err := rawTarget.StringSlice(key)
+		if err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		ids = append(ids, owned...)
+	}
+	for _, buildPhase := range target.BuildPhases() {
+		for _, file := range buildPhase.Files {
+			ids = append(ids, file.ID)
+		}
+	}
+
+	dependencyIDs, err := rawTarget.StringSlice("dependencies")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return nil, err
+	}
+	for _, dependencyID := range dependencyIDs {
+		dependency, err := objects.Object(dependencyID)
+		if err != nil {
+			return nil, err
+		}
+		if targetProxy, err := dependency.String("targetProxy"); err == nil {
+			ids = append(ids, targetProxy)
+		}
+	}
+
+	if productReference, err := rawTarget.String("productReference"); err == nil {
+		ids = append(ids, productReference)
+	} else if !serialized.IsKeyNotFoundError(err) {
+		return nil, err
+	}
+
+	if configurationListID, err := rawTarget.String("buildConfigurationList"); err == nil {
+		ids = append(ids, configurationListID)
+
+		configurationList, err := objects.Object(configurationListID)
+		if err != nil {
+			return nil, err
+		}
+		configurationIDs, err := configurationList.StringSlice("buildConfigurations")
+		if err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		ids = append(ids, configurationIDs...)
+	} else if !serialized.IsKeyNotFoundError(err) {
+		return nil, err
+	}
+
+	var uniqueIDs []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+	return uniqueIDs, nil
+}
+
+// remapIDs returns a deep copy of the value, with the IDs (strings and dictionary keys) replaced according to newIDs.
+func remapIDs(value interface{}, newIDs map[string]string) interface{} {
+	switch v := value.(type) {
+	case serialized.Object:
+		return remapIDs(map[string]interface{}(v), newIDs)
+	case map[string]interface{}:
+		copied := map[string]interface{}{}
+		for key, element := range v {
+			if newKey, ok := newIDs[key]; ok {
+				key = newKey
+			}
+			copied[key] = remapIDs(element, newIDs)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, element := range v {
+			copied[i] = remapIDs(element, newIDs)
+		}
+		return copied
+	case string:
+		if newID, ok := newIDs[v]; ok {
+			return newID
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// addProductReference adds the product file reference to the project's products group.
+func (p XcodeProj) addProductReference(productReference serialized.Object, productReferenceID string) error {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+	project, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return err
+	}
+
+	productRefGroupID, err := project.String("productRefGroup")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	productRefGroup, err := objects.Object(productRefGroupID)
+	if err != nil {
+		return err
+	}
+
+	children, err := productRefGroup.StringSlice("children")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return err
+	}
+	productRefGroup["children"] = rawStringSlice(append(children, productReferenceID))
+	return nil
+}
+
+// rawTargetAttributes returns the project's attributes.TargetAttributes, it is created if missing.
+func rawTargetAttributes(project serialized.Object) (serialized.Object, error) {
+	attributes, err := project.Object("attributes")
+	if err != nil {
+		if !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		attributes = map[string]interface{}{}
+		project["attributes"] = map[string]interface{}(attributes)
+	}
+
+	targetAttributes, err := attributes.Object("TargetAttributes")
+	if err != nil {
+		if !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		targetAttributes = map[string]interface{}{}
+		attributes["TargetAttributes"] = map[string]interface{}(targetAttributes)
+	}
+	return targetAttributes, nil
+}
@@ -0,0 +1,194 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_DuplicateBuildConfiguration(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	require.NoError(t, proj.DuplicateBuildConfiguration("Release", "Staging"))
+
+	configurationLists := []ConfigurationList{proj.Proj.BuildConfigurationList}
+	for _, target := range proj.Proj.Targets {
+		configurationLists = append(configurationLists, target.BuildConfigurationList)
+	}
+	for _, configurationList := range configurationLists {
+		release, ok := configurationList.BuildConfiguration("Release")
+		require.True(t, ok)
+		staging, ok := configurationList.BuildConfiguration("Staging")
+		require.True(t, ok)
+
+		require.NotEqual(t, release.ID, staging.ID)
+		require.Equal(t, release.BuildSettings, staging.BuildSettings)
+		require.Equal(t, "Staging", configurationList.BuildConfigurations[len(configurationList.BuildConfigurations)-1].Name)
+	}
+
+	err = proj.DuplicateBuildConfiguration("Release", "Staging")
+	require.EqualError(t, err, "build configuration (Staging) already exists in configuration list: "+proj.Proj.BuildConfigurationList.ID)
+
+	err = proj.DuplicateBuildConfiguration("AppStore", "Staging2")
+	require.EqualError(t, err, "failed to find build configuration with name: AppStore")
+
+	content, err := proj.perObjectModify()
+	require.NoError(t, err)
+	require.Equal(t, 4, strings.Count(string(content), "/* Staging */ = {"))
+}
+
+func TestXcodeProj_DuplicateSchemesForBuildConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	projectPth := filepath.Join(dir, "XcodeProj.xcodeproj")
+	schemesDir := filepath.Join(projectPth, "xcshareddata", "xcschemes")
+	require.NoError(t, os.MkdirAll(schemesDir, 0755))
+
+	scheme := `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1000"
+   version = "1.3">
+   <TestAction
+      buildConfiguration = "Debug">
+   </TestAction>
+   <LaunchAction
+      buildConfiguration = "Release">
+   </LaunchAction>
+   <ArchiveAction
+      buildConfiguration = "Release"
+      revealArchiveInOrganizer = "YES">
+   </ArchiveAction>
+</Scheme>
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(schemesDir, "XcodeProj.xcscheme"), []byte(scheme), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(schemesDir, "Tests.xcscheme"), []byte(strings.Replace(scheme, "Release", "Debug", -1)), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(testhelper.XcodeProjectTest), 0644))
+
+	proj, err := Open(projectPth)
+	require.NoError(t, err)
+	pths, err := proj.DuplicateSchemesForBuildConfiguration("Release", "Staging")
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(schemesDir, "XcodeProj Staging.xcscheme")}, pths)
+
+	exist, err := pathutil.IsPathExists(pths[0])
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	require.NoError(t, proj.Save())
+	content, err := ioutil.ReadFile(pths[0])
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(scheme, `buildConfiguration = "Release"`, `buildConfiguration = "Staging"`, -1), string(content))
+
+	_, err = proj.DuplicateSchemesForBuildConfiguration("Release", "Staging")
+	require.EqualError(t, err, "scheme already exists: "+pths[0])
+}
+
+func TestXcodeProj_DuplicateTarget(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	_, err = proj.DuplicateTarget("XcodeProj", "TodayExtension", "io.bitrise.TodayExtension")
+	require.EqualError(t, err, "target already exists with name: TodayExtension")
+
+	_, err = proj.DuplicateTarget("XcodeProj", "WhiteLabel", "")
+	require.EqualError(t, err, "empty bundle ID")
+
+	id, err := proj.DuplicateTarget("XcodeProj", "WhiteLabel", "io.bitrise.WhiteLabel")
+	require.NoError(t, err)
+
+	original, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	duplicate, ok := proj.Proj.TargetByName("WhiteLabel")
+	require.True(t, ok)
+	require.Equal(t, id, duplicate.ID)
+	require.Equal(t, id, proj.Proj.Targets[len(proj.Proj.Targets)-1].ID)
+
+	require.Equal(t, len(original.BuildPhases()), len(duplicate.BuildPhases()))
+	for i, buildPhase := range duplicate.BuildPhases() {
+		originalBuildPhase := original.BuildPhases()[i]
+		require.NotEqual(t, originalBuildPhase.ID, buildPhase.ID)
+		require.Equal(t, originalBuildPhase.Type, buildPhase.Type)
+		require.Equal(t, len(originalBuildPhase.Files), len(buildPhase.Files))
+		for j, file := range buildPhase.Files {
+			require.NotEqual(t, originalBuildPhase.Files[j].ID, file.ID)
+			require.Equal(t, originalBuildPhase.Files[j].FileRef, file.FileRef)
+		}
+	}
+
+	require.NotEqual(t, original.BuildConfigurationList.ID, duplicate.BuildConfigurationList.ID)
+	require.Equal(t, len(original.BuildConfigurationList.BuildConfigurations), len(duplicate.BuildConfigurationList.BuildConfigurations))
+	for i, buildConfiguration := range duplicate.BuildConfigurationList.BuildConfigurations {
+		originalBuildConfiguration := original.BuildConfigurationList.BuildConfigurations[i]
+		require.NotEqual(t, originalBuildConfiguration.ID, buildConfiguration.ID)
+		require.Equal(t, "com.bitrise.XcodeProj", originalBuildConfiguration.BuildSettings["PRODUCT_BUNDLE_IDENTIFIER"])
+		require.Equal(t, "io.bitrise.WhiteLabel", buildConfiguration.BuildSettings["PRODUCT_BUNDLE_IDENTIFIER"])
+
+		expectedBuildSettings := deepCopyObject(originalBuildConfiguration.BuildSettings)
+		expectedBuildSettings["PRODUCT_BUNDLE_IDENTIFIER"] = "io.bitrise.WhiteLabel"
+		require.Equal(t, expectedBuildSettings, buildConfiguration.BuildSettings)
+	}
+
+	require.Equal(t, 1, len(duplicate.Dependencies))
+	require.NotEqual(t, original.Dependencies[0].ID, duplicate.Dependencies[0].ID)
+	require.Equal(t, "TodayExtension", duplicate.Dependencies[0].Target.Name)
+
+	require.Equal(t, "WhiteLabel.app", duplicate.ProductReference.Path)
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	rawDuplicate, err := objects.Object(id)
+	require.NoError(t, err)
+	productReferenceID, err := rawDuplicate.String("productReference")
+	require.NoError(t, err)
+	require.NotEqual(t, "7D5B35FC20E28EE80022BAE6", productReferenceID)
+
+	_, err = proj.Proj.Attributes.TargetAttributes.Object(id)
+	require.NoError(t, err)
+
+	content, err := proj.perObjectModify()
+	require.NoError(t, err)
+	require.Contains(t, string(content), "/* Build configuration list for PBXNativeTarget \"WhiteLabel\" */ = {")
+	require.Contains(t, string(content), "\t\t\t\t"+productReferenceID+" /* WhiteLabel.app */,\n")
+
+	reparsed, err := parsePBXProjContent(content)
+	require.NoError(t, err)
+	require.Equal(t, proj.Proj, reparsed.Proj)
+}
+
+func Test_ownedTargetObjectIDs(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	rawTarget, err := objects.Object(target.ID)
+	require.NoError(t, err)
+
+	t.Log("build phase listed twice")
+	{
+		buildPhaseIDs, err := rawTarget.StringSlice("buildPhases")
+		require.NoError(t, err)
+		rawTarget["buildPhases"] = rawStringSlice(append(buildPhaseIDs, buildPhaseIDs[0]))
+		require.NoError(t, proj.reloadProj())
+		target, ok = proj.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+
+		ids, err := ownedTargetObjectIDs(target, rawTarget, objects)
+		require.NoError(t, err)
+		seen := map[string]bool{}
+		for _, id := range ids {
+			require.False(t, seen[id], id)
+			seen[id] = true
+		}
+		require.Contains(t, ids, buildPhaseIDs[0])
+	}
+}
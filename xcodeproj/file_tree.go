@@ -0,0 +1,300 @@
+package xcodeproj
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// File tree element types
+const (
+	GroupElementType        = "PBXGroup"
+	VariantGroupElementType = "PBXVariantGroup"
+	VersionGroupElementType = "XCVersionGroup"
+)
+
+// SkipChildren can be returned by the Walk callback to skip the children of the visited group.
+var SkipChildren = errors.New("skip children")
+
+// FileElement is a node of the project's file tree: *Group, *VariantGroup, *VersionGroup or *FileReference.
+type FileElement interface {
+	Base() *FileElementBase
+}
+
+// FileElementBase holds the properties shared by the file tree elements.
+type FileElementBase struct {
+	ID         string
+	Name       string
+	Path       string
+	SourceTree string
+
+	parent FileElement
+}
+
+// Base returns the shared properties of the element.
+func (e *FileElementBase) Base() *FileElementBase {
+	return e
+}
+
+// Parent returns the group containing the element, nil for the main group.
+func (e *FileElementBase) Parent() FileElement {
+	return e.parent
+}
+
+// DisplayName returns the name Xcode displays for the element: its name or the last component of its path.
+func (e *FileElementBase) DisplayName() string {
+	if e.Name != "" || e.Path == "" {
+		return e.Name
+	}
+	return path.Base(e.Path)
+}
+
+// Group represents a PBXGroup element
+type Group struct {
+	FileElementBase
+	Children []FileElement
+}
+
+// Walk calls fn for the group and every element below it in depth-first order.
+// If fn returns SkipChildren for a group, its children are skipped, any other error stops the walk.
+func (g *Group) Walk(fn func(element FileElement) error) error {
+	err := walkFileElement(g, fn)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+// VariantGroup represents a PBXVariantGroup element, the localized variants of a file.
+type VariantGroup struct {
+	FileElementBase
+	Children []FileElement
+}
+
+// VersionGroup represents an XCVersionGroup element, like a versioned Core Data model.
+type VersionGroup struct {
+	FileElementBase
+	Children         []FileElement
+	CurrentVersionID string
+	VersionGroupType string
+}
+
+// FileReference represents a PBXFileReference element
+type FileReference struct {
+	FileElementBase
+	LastKnownFileType string
+	ExplicitFileType  string
+}
+
+// ChildElements returns the children of the group like elements, nil for file references.
+func ChildElements(element FileElement) []FileElement {
+	switch e := element.(type) {
+	case *Group:
+		return e.Children
+	case *VariantGroup:
+		return e.Children
+	case *VersionGroup:
+		return e.Children
+	default:
+		return nil
+	}
+}
+
+func walkFileElement(element FileElement, fn func(element FileElement) error) error {
+	if err := fn(element); err != nil {
+		return err
+	}
+
+	for _, child := range ChildElements(element) {
+		if err := walkFileElement(child, fn); err != nil && err != SkipChildren {
+			return err
+		}
+	}
+	return nil
+}
+
+// MainGroup returns the root of the project's file tree.
+// The elements of unsupported types (like PBXReferenceProxy) are left out of the tree.
+func (p XcodeProj) MainGroup() (*Group, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	mainGroupID, err := project.String("mainGroup")
+	if err != nil {
+		return nil, fmt.Errorf("key mainGroup not found, project: %s, error: %s", project, err)
+	}
+
+	element, err := parseFileElement(mainGroupID, nil, objects, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	mainGroup, ok := element.(*Group)
+	if !ok {
+		return nil, fmt.Errorf("main group (%s) is not a %s element", mainGroupID, GroupElementType)
+	}
+	return mainGroup, nil
+}
+
+// FileElementByID returns the file tree element with the given ID.
+func (p XcodeProj) FileElementByID(id string) (FileElement, bool, error) {
+	mainGroup, err := p.MainGroup()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var found FileElement
+	errFound := errors.New("found")
+	if err := mainGroup.Walk(func(element FileElement) error {
+		if element.Base().ID == id {
+			found = element
+			return errFound
+		}
+		return nil
+	}); err != nil && err != errFound {
+		return nil, false, err
+	}
+
+	return found, found != nil, nil
+}
+
+// ResolvedPath is the resolved path of a file tree element.
+type ResolvedPath struct {
+	Path string
+	// Symbolic is true if the path is relative to a source tree which is only known at build time,
+	// in this case Path starts with the source tree build setting reference, like: $(BUILT_PRODUCTS_DIR)/App.app
+	Symbolic bool
+}
+
+// FileElementAbsolutePath returns the absolute path of the element on disk.
+// It fails if the element's path is only known at build time (like the products in BUILT_PRODUCTS_DIR),
+// use ResolveFileElementPath to resolve these paths.
+func (p XcodeProj) FileElementAbsolutePath(element FileElement) (string, error) {
+	resolved, err := p.ResolveFileElementPath(element, nil)
+	if err != nil {
+		return "", err
+	}
+	if resolved.Symbolic {
+		return "", fmt.Errorf("path is only known at build time: %s", resolved.Path)
+	}
+	return resolved.Path, nil
+}
+
+// ResolveFileElementPath resolves the path of the element, supporting every sourceTree kind:
+// <group>, <absolute>, SOURCE_ROOT and the build setting source trees (like BUILT_PRODUCTS_DIR, SDKROOT or DEVELOPER_DIR).
+// The build setting source trees are looked up in buildSettings (which can be nil),
+// if a source tree has no value the returned path is symbolic.
+func (p XcodeProj) ResolveFileElementPath(element FileElement, buildSettings serialized.Object) (ResolvedPath, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return ResolvedPath{}, err
+	}
+
+	var entries []projectEntry
+	for e := element; e != nil; e = e.Base().Parent() {
+		entries = append(entries, newProjectEntry(e.Base().ID, e.Base().Path, e.Base().SourceTree))
+	}
+
+	projectRoot, err := projectRootEntry(p.Proj.ID, p.Path, objects)
+	if err != nil {
+		return ResolvedPath{}, err
+	}
+
+	return resolveFileElementPath(append(entries, projectRoot), buildSettings)
+}
+
+func parseFileElement(id string, parent FileElement, objects serialized.Object, visited map[string]bool) (FileElement, error) {
+	if visited[id] {
+		return nil, fmt.Errorf("circular reference in project, id: %s", id)
+	}
+	visited[id] = true
+
+	raw, err := objects.Object(id)
+	if err != nil {
+		return nil, fmt.Errorf("object not found, id: %s, error: %s", id, err)
+	}
+
+	isa, err := raw.String("isa")
+	if err != nil {
+		return nil, err
+	}
+
+	base := FileElementBase{ID: id, parent: parent}
+	for key, value := range map[string]*string{"name": &base.Name, "path": &base.Path, "sourceTree": &base.SourceTree} {
+		if *value, err = raw.String(key); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+	}
+
+	switch isa {
+	case fileReferenceElementType:
+		fileReference := &FileReference{FileElementBase: base}
+		if fileReference.LastKnownFileType, err = raw.String("lastKnownFileType"); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		if fileReference.ExplicitFileType, err = raw.String("explicitFileType"); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		return fileReference, nil
+	case GroupElementType:
+		group := &Group{FileElementBase: base}
+		group.Children, err = parseChildElements(group, raw, objects, visited)
+		if err != nil {
+			return nil, err
+		}
+		return group, nil
+	case VariantGroupElementType:
+		group := &VariantGroup{FileElementBase: base}
+		group.Children, err = parseChildElements(group, raw, objects, visited)
+		if err != nil {
+			return nil, err
+		}
+		return group, nil
+	case VersionGroupElementType:
+		group := &VersionGroup{FileElementBase: base}
+		if group.CurrentVersionID, err = raw.String("currentVersion"); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		if group.VersionGroupType, err = raw.String("versionGroupType"); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		group.Children, err = parseChildElements(group, raw, objects, visited)
+		if err != nil {
+			return nil, err
+		}
+		return group, nil
+	default:
+		return nil, nil
+	}
+}
+
+func parseChildElements(parent FileElement, raw serialized.Object, objects serialized.Object, visited map[string]bool) ([]FileElement, error) {
+	childIDs, err := raw.StringSlice("children")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var children []FileElement
+	for _, childID := range childIDs {
+		child, err := parseFileElement(childID, parent, objects, visited)
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	return children, nil
+}
@@ -0,0 +1,298 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+const groupSourceTree = "<group>"
+
+// lastKnownFileTypes maps the lowercased file extensions to Xcode's file types.
+var lastKnownFileTypes = map[string]string{
+	"swift":            "sourcecode.swift",
+	"m":                "sourcecode.c.objc",
+	"mm":               "sourcecode.cpp.objcpp",
+	"c":                "sourcecode.c.c",
+	"cc":               "sourcecode.cpp.cpp",
+	"cpp":              "sourcecode.cpp.cpp",
+	"h":                "sourcecode.c.h",
+	"hpp":              "sourcecode.cpp.h",
+	"metal":            "sourcecode.metal",
+	"storyboard":       "file.storyboard",
+	"xib":              "file.xib",
+	"xcassets":         "folder.assetcatalog",
+	"strings":          "text.plist.strings",
+	"stringsdict":      "text.plist.stringsdict",
+	"plist":            "text.plist.xml",
+	"entitlements":     "text.plist.entitlements",
+	"xcconfig":         "text.xcconfig",
+	"json":             "text.json",
+	"graphql":          "text",
+	"txt":              "text",
+	"md":               "net.daringfireball.markdown",
+	"html":             "text.html",
+	"sh":               "text.script.sh",
+	"png":              "image.png",
+	"jpg":              "image.jpeg",
+	"jpeg":             "image.jpeg",
+	"pdf":              "image.pdf",
+	"framework":        "wrapper.framework",
+	"xcframework":      "wrapper.xcframework",
+	"a":                "archive.ar",
+	"dylib":            "compiled.mach-o.dylib",
+	"tbd":              "sourcecode.text-based-dylib-definition",
+	"bundle":           "wrapper.plug-in",
+	"app":              "wrapper.application",
+	"appex":            "wrapper.app-extension",
+	"xcdatamodeld":     "wrapper.xcdatamodeld",
+	"xcdatamodel":      "wrapper.xcdatamodel",
+	"xcmappingmodel":   "wrapper.xcmappingmodel",
+	"intentdefinition": "file.intentdefinition",
+	"xctestplan":       "text",
+	"modulemap":        "sourcecode.module-map",
+}
+
+// LastKnownFileType returns the Xcode file type of the file, inferred from its extension (like sourcecode.swift for .swift files).
+// It returns "file" for the unknown extensions.
+func LastKnownFileType(pth string) string {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(pth), "."))
+	if fileType, ok := lastKnownFileTypes[ext]; ok {
+		return fileType
+	}
+	return "file"
+}
+
+// RawObject ...
+func (g Group) RawObject() (map[string]interface{}, error) {
+	var childIDs []string
+	for _, child := range g.Children {
+		childIDs = append(childIDs, child.Base().ID)
+	}
+
+	raw := map[string]interface{}{
+		"isa":      GroupElementType,
+		"children": rawStringSlice(childIDs),
+	}
+	g.FileElementBase.apply(raw)
+	return raw, nil
+}
+
+// RawObject ...
+func (f FileReference) RawObject() (map[string]interface{}, error) {
+	if f.Path == "" {
+		return nil, fmt.Errorf("file reference has no path")
+	}
+
+	raw := map[string]interface{}{"isa": fileReferenceElementType}
+	if f.ExplicitFileType != "" {
+		raw["explicitFileType"] = f.ExplicitFileType
+	}
+	if f.LastKnownFileType != "" {
+		raw["lastKnownFileType"] = f.LastKnownFileType
+	}
+	f.FileElementBase.apply(raw)
+	return raw, nil
+}
+
+// apply writes the name, path and sourceTree of the element to the raw object, sourceTree defaults to <group>.
+func (e FileElementBase) apply(raw map[string]interface{}) {
+	if e.Name != "" {
+		raw["name"] = e.Name
+	}
+	if e.Path != "" {
+		raw["path"] = e.Path
+	}
+
+	sourceTree := e.SourceTree
+	if sourceTree == "" {
+		sourceTree = groupSourceTree
+	}
+	raw["sourceTree"] = sourceTree
+}
+
+// AddGroup adds a new group as the last child of the parent group and returns its ID.
+// The group's ID and children are ignored, the group is created empty.
+func (p *XcodeProj) AddGroup(parentGroupID string, group Group) (string, error) {
+	if group.Name == "" && group.Path == "" {
+		return "", fmt.Errorf("group has neither name nor path")
+	}
+	group.Children = nil
+
+	return p.addFileElement(parentGroupID, group, group.FileElementBase)
+}
+
+// AddFileReference adds a new file reference as the last child of the parent group and returns its ID.
+// If the reference has neither LastKnownFileType nor ExplicitFileType, the LastKnownFileType is inferred from the path's extension.
+func (p *XcodeProj) AddFileReference(parentGroupID string, file FileReference) (string, error) {
+	if file.LastKnownFileType == "" && file.ExplicitFileType == "" {
+		file.LastKnownFileType = LastKnownFileType(file.Path)
+	}
+
+	return p.addFileElement(parentGroupID, file, file.FileElementBase)
+}
+
+func (p *XcodeProj) addFileElement(parentGroupID string, element PBXObject, base FileElementBase) (string, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+
+	rawParent, err := objects.Object(parentGroupID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find group with id: %s", parentGroupID)
+	}
+	if isa, err := rawParent.String("isa"); err != nil {
+		return "", err
+	} else if isa != GroupElementType {
+		return "", fmt.Errorf("parent (%s) is not a %s element", parentGroupID, GroupElementType)
+	}
+
+	childIDs, err := rawParent.StringSlice("children")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return "", err
+	}
+	for _, childID := range childIDs {
+		rawChild, err := objects.Object(childID)
+		if err != nil {
+			continue
+		}
+		name, _ := rawChild.String("name")
+		pth, _ := rawChild.String("path")
+		if name == base.Name && pth == base.Path {
+			return "", fmt.Errorf("group (%s) already contains: %s", parentGroupID, base.DisplayName())
+		}
+	}
+
+	id, err := p.InsertObject(element)
+	if err != nil {
+		return "", err
+	}
+	rawParent["children"] = rawStringSlice(append(childIDs, id))
+
+	return id, p.reloadProj()
+}
+
+// fileBuildPhase is a new, empty build phase of the given type.
+type fileBuildPhase BuildPhaseType
+
+// RawObject ...
+func (t fileBuildPhase) RawObject() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"isa":                                string(t),
+		"buildActionMask":                    "2147483647",
+		"files":                              []interface{}{},
+		"runOnlyForDeploymentPostprocessing": rawBool(false),
+	}, nil
+}
+
+// AddFileToTargets adds the file tree element (like a file reference) to the given build phase
+// (SourcesBuildPhaseType, ResourcesBuildPhaseType or FrameworksBuildPhaseType) of the targets.
+// If a target has no build phase of the given type, a new phase is added as the target's last build phase.
+// Each target is handled once, targets which already contain the file in the build phase are skipped.
+// The IDs of the new build files are returned in the order of the targets.
+func (p *XcodeProj) AddFileToTargets(fileID string, buildPhaseType BuildPhaseType, targetNames ...string) ([]string, error) {
+	switch buildPhaseType {
+	case SourcesBuildPhaseType, ResourcesBuildPhaseType, FrameworksBuildPhaseType:
+	default:
+		return nil, fmt.Errorf("unsupported build phase type: %s", buildPhaseType)
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	rawFile, err := objects.Object(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find file with id: %s", fileID)
+	}
+	if isa, err := rawFile.String("isa"); err != nil {
+		return nil, err
+	} else if isa != fileReferenceElementType && isa != VariantGroupElementType && isa != VersionGroupElementType {
+		return nil, fmt.Errorf("element (%s) is a %s, can not be added to a build phase", fileID, isa)
+	}
+
+	var targets []Target
+	seen := map[string]bool{}
+	for _, targetName := range targetNames {
+		if seen[targetName] {
+			continue
+		}
+		seen[targetName] = true
+
+		target, ok := p.Proj.TargetByName(targetName)
+		if !ok {
+			return nil, fmt.Errorf("failed to find target with name: %s", targetName)
+		}
+		if targetContainsFile(target, buildPhaseType, fileID) {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	var buildFileIDs []string
+	for _, target := range targets {
+		rawTarget, err := objects.Object(target.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		buildPhaseID := ""
+		for _, buildPhase := range target.BuildPhases() {
+			if buildPhase.Type == buildPhaseType {
+				buildPhaseID = buildPhase.ID
+				break
+			}
+		}
+		if buildPhaseID == "" {
+			if buildPhaseID, err = p.InsertObject(fileBuildPhase(buildPhaseType)); err != nil {
+				return nil, err
+			}
+
+			buildPhaseIDs, err := rawTarget.StringSlice("buildPhases")
+			if err != nil && !serialized.IsKeyNotFoundError(err) {
+				return nil, err
+			}
+			rawTarget["buildPhases"] = rawStringSlice(append(buildPhaseIDs, buildPhaseID))
+		}
+
+		rawBuildPhase, err := objects.Object(buildPhaseID)
+		if err != nil {
+			return nil, err
+		}
+
+		buildFileID, err := p.InsertObject(BuildFile{FileRef: fileID})
+		if err != nil {
+			return nil, err
+		}
+
+		files, err := rawBuildPhase.StringSlice("files")
+		if err != nil && !serialized.IsKeyNotFoundError(err) {
+			return nil, err
+		}
+		rawBuildPhase["files"] = rawStringSlice(append(files, buildFileID))
+
+		buildFileIDs = append(buildFileIDs, buildFileID)
+	}
+
+	return buildFileIDs, p.reloadProj()
+}
+
+// targetContainsFile returns true if a build phase of the given type of the target
+// already has a build file for the file tree element.
+func targetContainsFile(target Target, buildPhaseType BuildPhaseType, fileID string) bool {
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type != buildPhaseType {
+			continue
+		}
+		for _, file := range buildPhase.Files {
+			if file.FileRef == fileID {
+				return true
+			}
+		}
+	}
+	return false
+}
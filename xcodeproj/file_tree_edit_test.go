@@ -0,0 +1,144 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastKnownFileType(t *testing.T) {
+	tests := []struct {
+		pth  string
+		want string
+	}{
+		{pth: "Generated/API.swift", want: "sourcecode.swift"},
+		{pth: "Bridge.M", want: "sourcecode.c.objc"},
+		{pth: "Assets.xcassets", want: "folder.assetcatalog"},
+		{pth: "Frameworks/Lib.xcframework", want: "wrapper.xcframework"},
+		{pth: "Info.plist", want: "text.plist.xml"},
+		{pth: "LICENSE", want: "file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pth, func(t *testing.T) {
+			require.Equal(t, tt.want, LastKnownFileType(tt.pth))
+		})
+	}
+}
+
+func TestXcodeProj_AddFileToTargets(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+	proj.IDGenerator = NewSeededObjectIDGenerator(1)
+
+	groupID, err := proj.AddGroup("7D5B35FE20E28EE80022BAE6", Group{FileElementBase: FileElementBase{Path: "Generated"}})
+	require.NoError(t, err)
+
+	_, err = proj.AddGroup("7D5B35FE20E28EE80022BAE6", Group{FileElementBase: FileElementBase{Path: "Generated"}})
+	require.EqualError(t, err, "group (7D5B35FE20E28EE80022BAE6) already contains: Generated")
+
+	_, err = proj.AddGroup("7D5B35FF20E28EE80022BAE6", Group{FileElementBase: FileElementBase{Name: "Generated"}})
+	require.EqualError(t, err, "parent (7D5B35FF20E28EE80022BAE6) is not a PBXGroup element")
+
+	fileID, err := proj.AddFileReference(groupID, FileReference{FileElementBase: FileElementBase{Path: "API.swift"}})
+	require.NoError(t, err)
+
+	element, found, err := proj.FileElementByID(fileID)
+	require.NoError(t, err)
+	require.True(t, found)
+	fileReference := element.(*FileReference)
+	require.Equal(t, "sourcecode.swift", fileReference.LastKnownFileType)
+	require.Equal(t, "<group>", fileReference.SourceTree)
+	require.Equal(t, groupID, fileReference.Parent().Base().ID)
+
+	buildFileIDs, err := proj.AddFileToTargets(fileID, SourcesBuildPhaseType, "XcodeProj", "TodayExtension")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(buildFileIDs))
+
+	_, err = proj.AddFileToTargets(fileID, CopyFilesBuildPhaseType, "XcodeProj")
+	require.EqualError(t, err, "unsupported build phase type: PBXCopyFilesBuildPhase")
+
+	_, err = proj.AddFileToTargets(groupID, SourcesBuildPhaseType, "XcodeProj")
+	require.EqualError(t, err, "element ("+groupID+") is a PBXGroup, can not be added to a build phase")
+
+	target, ok := proj.Proj.TargetByName("TodayExtension")
+	require.True(t, ok)
+	var sourceFileRefs []string
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type == SourcesBuildPhaseType {
+			for _, file := range buildPhase.Files {
+				sourceFileRefs = append(sourceFileRefs, file.FileRef)
+			}
+		}
+	}
+	require.Contains(t, sourceFileRefs, fileID)
+
+	t.Log("missing build phase is created")
+	{
+		objects, err := proj.RawProj.Object("objects")
+		require.NoError(t, err)
+		rawTarget, err := objects.Object("7D0342F020F4BA280050B6A6")
+		require.NoError(t, err)
+		rawTarget["buildPhases"] = []interface{}{"7D0342ED20F4BA280050B6A6"}
+		require.NoError(t, proj.reloadProj())
+
+		_, err = proj.AddFileToTargets(fileID, ResourcesBuildPhaseType, "XcodeProjUITests")
+		require.NoError(t, err)
+
+		target, ok := proj.Proj.TargetByName("XcodeProjUITests")
+		require.True(t, ok)
+		buildPhases := target.BuildPhases()
+		require.Equal(t, 2, len(buildPhases))
+		require.Equal(t, ResourcesBuildPhaseType, buildPhases[1].Type)
+		require.Equal(t, fileID, buildPhases[1].Files[0].FileRef)
+	}
+
+	t.Log("saved with Xcode comments")
+	{
+		content, err := proj.perObjectModify()
+		require.NoError(t, err)
+
+		require.Contains(t, string(content), "\t\t"+groupID+" /* Generated */ = {\n\t\t\tisa = PBXGroup;\n\t\t\tchildren = (\n\t\t\t\t"+fileID+" /* API.swift */,\n\t\t\t);\n\t\t\tpath = Generated;\n\t\t\tsourceTree = \"<group>\";\n\t\t};\n")
+		require.Contains(t, string(content), "\t\t"+fileID+" /* API.swift */ = {isa = PBXFileReference; lastKnownFileType = sourcecode.swift; path = API.swift; sourceTree = \"<group>\"; };\n")
+		require.Contains(t, string(content), "\t\t"+buildFileIDs[0]+" /* API.swift in Sources */ = {isa = PBXBuildFile; fileRef = "+fileID+" /* API.swift */; };\n")
+		require.Contains(t, string(content), "\t\t\t\t"+buildFileIDs[1]+" /* API.swift in Sources */,\n")
+		require.Contains(t, string(content), "\t\t\t\t"+groupID+" /* Generated */,\n")
+
+		reparsed, err := parsePBXProjContent(content)
+		require.NoError(t, err)
+		require.Equal(t, proj.Proj, reparsed.Proj)
+	}
+
+	t.Log("targets already containing the file are skipped")
+	{
+		otherFileID, err := proj.AddFileReference(groupID, FileReference{FileElementBase: FileElementBase{Path: "Model.swift"}})
+		require.NoError(t, err)
+
+		otherBuildFileIDs, err := proj.AddFileToTargets(otherFileID, SourcesBuildPhaseType, "XcodeProj", "XcodeProj")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(otherBuildFileIDs))
+
+		otherBuildFileIDs, err = proj.AddFileToTargets(otherFileID, SourcesBuildPhaseType, "XcodeProj", "TodayExtension")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(otherBuildFileIDs))
+
+		otherBuildFileIDs, err = proj.AddFileToTargets(otherFileID, SourcesBuildPhaseType, "XcodeProj", "TodayExtension")
+		require.NoError(t, err)
+		require.Equal(t, 0, len(otherBuildFileIDs))
+
+		for _, targetName := range []string{"XcodeProj", "TodayExtension"} {
+			target, ok := proj.Proj.TargetByName(targetName)
+			require.True(t, ok)
+			count := 0
+			for _, buildPhase := range target.BuildPhases() {
+				for _, file := range buildPhase.Files {
+					if file.FileRef == otherFileID {
+						count++
+					}
+				}
+			}
+			require.Equal(t, 1, count, targetName)
+		}
+	}
+}
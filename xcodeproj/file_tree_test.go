@@ -0,0 +1,164 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_MainGroup(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Path = "/tmp/Sample/XcodeProj.xcodeproj"
+
+	mainGroup, err := proj.MainGroup()
+	require.NoError(t, err)
+	require.Equal(t, "7D5B35F320E28EE80022BAE6", mainGroup.ID)
+	require.Nil(t, mainGroup.Parent())
+
+	var displayNames []string
+	for _, child := range mainGroup.Children {
+		displayNames = append(displayNames, child.Base().DisplayName())
+	}
+	require.Equal(t, []string{"XcodeProj", "XcodeProjUITests", "TodayExtension", "Frameworks", "Products"}, displayNames)
+
+	t.Log("walk")
+	{
+		var visited []string
+		err := mainGroup.Walk(func(element FileElement) error {
+			visited = append(visited, element.Base().DisplayName())
+			if group, ok := element.(*Group); ok && group.Name == "Products" {
+				return SkipChildren
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"",
+			"XcodeProj", "AppDelegate.swift", "ViewController.swift", "Main.storyboard", "Base", "Assets.xcassets", "LaunchScreen.storyboard", "Base", "Info.plist",
+			"XcodeProjUITests", "XcodeProjUITests.swift", "Info.plist",
+			"TodayExtension", "TodayExtension.entitlements", "TodayViewController.swift", "MainInterface.storyboard", "Base", "Info.plist",
+			"Frameworks", "CloudKit.framework", "NotificationCenter.framework",
+			"Products",
+		}, visited)
+	}
+
+	t.Log("typed elements with parent links")
+	{
+		element, found, err := proj.FileElementByID("7D5B360420E28EE80022BAE6")
+		require.NoError(t, err)
+		require.True(t, found)
+
+		fileReference, ok := element.(*FileReference)
+		require.True(t, ok)
+		require.Equal(t, "Base", fileReference.Name)
+		require.Equal(t, "Base.lproj/Main.storyboard", fileReference.Path)
+		require.Equal(t, "file.storyboard", fileReference.LastKnownFileType)
+
+		variantGroup, ok := fileReference.Parent().(*VariantGroup)
+		require.True(t, ok)
+		require.Equal(t, "Main.storyboard", variantGroup.Name)
+		require.Equal(t, "XcodeProj", variantGroup.Parent().Base().Path)
+
+		_, found, err = proj.FileElementByID("7D5B35FB20E28EE80022BAE6")
+		require.NoError(t, err)
+		require.False(t, found)
+	}
+
+	t.Log("absolute path")
+	{
+		element, _, err := proj.FileElementByID("7D5B360420E28EE80022BAE6")
+		require.NoError(t, err)
+		pth, err := proj.FileElementAbsolutePath(element)
+		require.NoError(t, err)
+		require.Equal(t, "/tmp/Sample/XcodeProj/Base.lproj/Main.storyboard", pth)
+
+		pth, err = proj.FileElementAbsolutePath(mainGroup)
+		require.NoError(t, err)
+		require.Equal(t, "/tmp/Sample", pth)
+	}
+}
+
+func TestXcodeProj_ResolveFileElementPath(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Path = "/tmp/Sample/XcodeProj.xcodeproj"
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	infoPlist, err := objects.Object("7D0342F520F4BA280050B6A6")
+	require.NoError(t, err)
+	infoPlist["sourceTree"] = "SOURCE_ROOT"
+	infoPlist["path"] = "Config/Info.plist"
+	todayViewController, err := objects.Object("7D03431220F4BB070050B6A6")
+	require.NoError(t, err)
+	todayViewController["sourceTree"] = "SHARED_SOURCES"
+
+	buildSettings := serialized.Object{
+		"BUILT_PRODUCTS_DIR": "/tmp/DerivedData/Build/Products/Debug-iphoneos",
+		"DEVELOPER_DIR":      "/Applications/Xcode.app/Contents/Developer",
+		"SDKROOT":            "$(DEVELOPER_DIR)/Platforms/iPhoneOS.platform/Developer/SDKs/iPhoneOS.sdk",
+	}
+
+	tests := []struct {
+		name          string
+		id            string
+		buildSettings serialized.Object
+		want          ResolvedPath
+	}{
+		{
+			name: "group",
+			id:   "7D5B360420E28EE80022BAE6",
+			want: ResolvedPath{Path: "/tmp/Sample/XcodeProj/Base.lproj/Main.storyboard"},
+		},
+		{
+			name: "source root",
+			id:   "7D0342F520F4BA280050B6A6",
+			want: ResolvedPath{Path: "/tmp/Sample/Config/Info.plist"},
+		},
+		{
+			name: "built products dir without build settings",
+			id:   "7D5B35FC20E28EE80022BAE6",
+			want: ResolvedPath{Path: "$(BUILT_PRODUCTS_DIR)/XcodeProj.app", Symbolic: true},
+		},
+		{
+			name:          "built products dir",
+			id:            "7D5B35FC20E28EE80022BAE6",
+			buildSettings: buildSettings,
+			want:          ResolvedPath{Path: "/tmp/DerivedData/Build/Products/Debug-iphoneos/XcodeProj.app"},
+		},
+		{
+			name:          "sdk root referencing other build settings",
+			id:            "7D03432020F4BB8D0050B6A6",
+			buildSettings: buildSettings,
+			want:          ResolvedPath{Path: "/Applications/Xcode.app/Contents/Developer/Platforms/iPhoneOS.platform/Developer/SDKs/iPhoneOS.sdk/System/Library/Frameworks/CloudKit.framework"},
+		},
+		{
+			name:          "custom source tree",
+			id:            "7D03431220F4BB070050B6A6",
+			buildSettings: buildSettings,
+			want:          ResolvedPath{Path: "$(SHARED_SOURCES)/TodayViewController.swift", Symbolic: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			element, found, err := proj.FileElementByID(tt.id)
+			require.NoError(t, err)
+			require.True(t, found)
+
+			got, err := proj.ResolveFileElementPath(element, tt.buildSettings)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Log("absolute path of build time path")
+	{
+		element, _, err := proj.FileElementByID("7D5B35FC20E28EE80022BAE6")
+		require.NoError(t, err)
+		_, err = proj.FileElementAbsolutePath(element)
+		require.EqualError(t, err, "path is only known at build time: $(BUILT_PRODUCTS_DIR)/XcodeProj.app")
+	}
+}
@@ -0,0 +1,301 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// BuildSettingCycleError is returned when build settings reference each other in a cycle.
+type BuildSettingCycleError struct {
+	// Chain lists the build settings involved in the cycle, starting and ending with the same setting.
+	Chain []string
+}
+
+// Error implements the error interface
+func (e BuildSettingCycleError) Error() string {
+	return fmt.Sprintf("build setting reference cycle found: %s", strings.Join(e.Chain, " -> "))
+}
+
+// IsBuildSettingCycleError reports whatever the given error is an instance of BuildSettingCycleError
+func IsBuildSettingCycleError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(BuildSettingCycleError)
+	return ok
+}
+
+// ExpandBuildSetting expands the build setting references (macros) in value using buildSettings.
+//
+// Supported reference forms: $(NAME), ${NAME}, $NAME and nested references, like: $(NAME_$(CONFIGURATION)).
+// The evaluation operators can be chained after the name, like: $(PRODUCT_NAME:lower:rfc1034identifier).
+// Supported operators: rfc1034identifier, c99extidentifier, identifier, lower, upper, base, dir, file, suffix,
+// standardizepath and default=VALUE.
+// Undefined build settings expand to an empty string (as in Xcode),
+// BuildSettingCycleError is returned if the settings reference each other in a cycle.
+//
+// **Example:**
+// PRODUCT_BUNDLE_IDENTIFIER in the .pbxproj: Bitrise.Test.$(PRODUCT_NAME:rfc1034identifier).Suffix
+// PRODUCT_BUNDLE_IDENTIFIER after expansion: Bitrise.Test.Sample-App.Suffix
+func ExpandBuildSetting(value string, buildSettings serialized.Object) (string, error) {
+	expander := objectMacroExpander{buildSettings: buildSettings, expanded: map[string]string{}}
+	return expandMacros(value, expander.resolve)
+}
+
+// objectMacroExpander resolves the references from a flat build settings object.
+type objectMacroExpander struct {
+	buildSettings serialized.Object
+	chain         []string
+	expanded      map[string]string
+}
+
+func (e *objectMacroExpander) resolve(name string) (string, error) {
+	if value, ok := e.expanded[name]; ok {
+		return value, nil
+	}
+
+	for i, n := range e.chain {
+		if n == name {
+			return "", BuildSettingCycleError{Chain: append(append([]string{}, e.chain[i:]...), name)}
+		}
+	}
+
+	raw, ok := e.buildSettings[name]
+	if !ok {
+		return "", nil
+	}
+
+	e.chain = append(e.chain, name)
+	defer func() { e.chain = e.chain[:len(e.chain)-1] }()
+
+	value, err := expandMacroValue(raw, func(element string) (string, error) {
+		return expandMacros(element, e.resolve)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	e.expanded[name] = value
+	return value, nil
+}
+
+// expandMacroValue expands a build setting value, which is either a string or an array of strings.
+// The expanded array elements are joined by space, the empty ones are dropped and
+// the literal elements containing space are quoted.
+func expandMacroValue(raw interface{}, expand func(string) (string, error)) (string, error) {
+	elements, ok := raw.([]interface{})
+	if !ok {
+		str, ok := raw.(string)
+		if !ok {
+			str = fmt.Sprintf("%v", raw)
+		}
+		return expand(str)
+	}
+
+	var expandedElements []string
+	for _, element := range elements {
+		str, ok := element.(string)
+		if !ok {
+			continue
+		}
+
+		expanded, err := expand(str)
+		if err != nil {
+			return "", err
+		}
+		if expanded == "" {
+			continue
+		}
+		if strings.Contains(str, " ") && !strings.Contains(str, "$") {
+			expanded = `"` + expanded + `"`
+		}
+		expandedElements = append(expandedElements, expanded)
+	}
+	return strings.Join(expandedElements, " "), nil
+}
+
+// expandMacros replaces the $(NAME[:operator...]), ${NAME[:operator...]} and $NAME references in value,
+// resolve returns the expanded value of a referenced build setting.
+func expandMacros(value string, resolve func(name string) (string, error)) (string, error) {
+	var expanded strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			expanded.WriteByte(value[i])
+			continue
+		}
+
+		switch value[i+1] {
+		case '(', '{':
+			end := closingBracketIndex(value, i+1)
+			if end == -1 {
+				expanded.WriteByte(value[i])
+				continue
+			}
+
+			referenced, err := expandMacroReference(value[i+2:end], resolve)
+			if err != nil {
+				return "", err
+			}
+			expanded.WriteString(referenced)
+			i = end
+		default:
+			end := i + 1
+			for end < len(value) && isBuildSettingNameCharacter(value[end]) {
+				end++
+			}
+			if end == i+1 {
+				expanded.WriteByte(value[i])
+				continue
+			}
+
+			referenced, err := resolve(value[i+1 : end])
+			if err != nil {
+				return "", err
+			}
+			expanded.WriteString(referenced)
+			i = end - 1
+		}
+	}
+	return expanded.String(), nil
+}
+
+// expandMacroReference evaluates the content of a $(...) reference: the (possibly nested) name and the operators.
+func expandMacroReference(reference string, resolve func(name string) (string, error)) (string, error) {
+	parts := splitMacroOperators(reference)
+
+	name, err := expandMacros(parts[0], resolve)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := resolve(name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, operator := range parts[1:] {
+		if strings.HasPrefix(operator, "default=") {
+			if value == "" {
+				value, err = expandMacros(strings.TrimPrefix(operator, "default="), resolve)
+				if err != nil {
+					return "", err
+				}
+			}
+			continue
+		}
+
+		value, err = applyMacroOperator(operator, value)
+		if err != nil {
+			return "", fmt.Errorf("failed to expand $(%s): %s", reference, err)
+		}
+	}
+
+	return value, nil
+}
+
+// splitMacroOperators splits the reference content by the ':' separators outside of the nested references.
+func splitMacroOperators(reference string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(reference); i++ {
+		switch reference[i] {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, reference[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, reference[start:])
+}
+
+func applyMacroOperator(operator, value string) (string, error) {
+	switch operator {
+	case "rfc1034identifier":
+		return replaceCharacters(value, func(r rune) bool {
+			return isASCIIAlphanumeric(r) || r == '-' || r == '.'
+		}, '-'), nil
+	case "c99extidentifier", "identifier":
+		identifier := replaceCharacters(value, func(r rune) bool {
+			if operator == "c99extidentifier" && r > unicode.MaxASCII {
+				return unicode.IsLetter(r) || unicode.IsDigit(r)
+			}
+			return isASCIIAlphanumeric(r) || r == '_'
+		}, '_')
+		if identifier != "" && identifier[0] >= '0' && identifier[0] <= '9' {
+			identifier = "_" + identifier
+		}
+		return identifier, nil
+	case "lower":
+		return strings.ToLower(value), nil
+	case "upper":
+		return strings.ToUpper(value), nil
+	case "dir":
+		if idx := strings.LastIndex(value, "/"); idx != -1 {
+			return value[:idx+1], nil
+		}
+		return "", nil
+	case "file":
+		return value[strings.LastIndex(value, "/")+1:], nil
+	case "base":
+		file := value[strings.LastIndex(value, "/")+1:]
+		return strings.TrimSuffix(file, path.Ext(file)), nil
+	case "suffix":
+		return path.Ext(value[strings.LastIndex(value, "/")+1:]), nil
+	case "standardizepath":
+		if value == "" {
+			return "", nil
+		}
+		return path.Clean(value), nil
+	default:
+		return "", fmt.Errorf("unknown build setting operator: %s", operator)
+	}
+}
+
+func replaceCharacters(value string, allowed func(rune) bool, replacement rune) string {
+	return strings.Map(func(r rune) rune {
+		if allowed(r) {
+			return r
+		}
+		return replacement
+	}, value)
+}
+
+func isASCIIAlphanumeric(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
+
+func closingBracketIndex(value string, openIdx int) int {
+	opening := value[openIdx]
+	closing := byte(')')
+	if opening == '{' {
+		closing = '}'
+	}
+
+	depth := 0
+	for i := openIdx; i < len(value); i++ {
+		switch value[i] {
+		case opening:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isBuildSettingNameCharacter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
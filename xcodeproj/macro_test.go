@@ -0,0 +1,88 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandBuildSetting(t *testing.T) {
+	buildSettings := serialized.Object{
+		"PRODUCT_NAME":         "Sample App",
+		"CONFIGURATION":        "Debug",
+		"NAME_Debug":           "debug-name",
+		"BUNDLE_ID":            "com.bitrise.$(PRODUCT_NAME:rfc1034identifier)",
+		"MODULE":               "1st module-name",
+		"FILE_PATH":            "/tmp/./dir/../MyFile.tar.gz",
+		"EMPTY":                "",
+		"OTHER_LDFLAGS":        []interface{}{"$(inherited)", "-ObjC", "path with space"},
+		"LOWER_PRODUCT_NAME":   "$(PRODUCT_NAME:lower:rfc1034identifier)",
+		"SIMPLE_REFERENCE":     "$CONFIGURATION.$PRODUCT_NAMEsuffix",
+		"DEFAULT_IN_REFERENCE": "$(EMPTY:default=$(CONFIGURATION))",
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain value", value: "com.bitrise", want: "com.bitrise"},
+		{name: "parentheses and braces", value: "$(CONFIGURATION)-${CONFIGURATION}", want: "Debug-Debug"},
+		{name: "simple reference", value: "$CONFIGURATION.suffix", want: "Debug.suffix"},
+		{name: "simple reference uses the longest name", value: "$(SIMPLE_REFERENCE)", want: "Debug."},
+		{name: "nested reference", value: "$(NAME_$(CONFIGURATION))", want: "debug-name"},
+		{name: "cross reference", value: "$(BUNDLE_ID).suffix", want: "com.bitrise.Sample-App.suffix"},
+		{name: "undefined reference", value: "prefix$(UNDEFINED)", want: "prefix"},
+		{name: "array value", value: "$(OTHER_LDFLAGS)", want: `-ObjC "path with space"`},
+		{name: "unclosed reference", value: "$(PRODUCT_NAME", want: "$(PRODUCT_NAME"},
+		{name: "rfc1034identifier", value: "$(PRODUCT_NAME:rfc1034identifier)", want: "Sample-App"},
+		{name: "c99extidentifier", value: "$(MODULE:c99extidentifier)", want: "_1st_module_name"},
+		{name: "identifier", value: "$(MODULE:identifier)", want: "_1st_module_name"},
+		{name: "lower", value: "$(PRODUCT_NAME:lower)", want: "sample app"},
+		{name: "upper", value: "$(PRODUCT_NAME:upper)", want: "SAMPLE APP"},
+		{name: "chained operators", value: "$(LOWER_PRODUCT_NAME)", want: "sample-app"},
+		{name: "dir", value: "$(FILE_PATH:dir)", want: "/tmp/./dir/../"},
+		{name: "file", value: "$(FILE_PATH:file)", want: "MyFile.tar.gz"},
+		{name: "base", value: "$(FILE_PATH:base)", want: "MyFile.tar"},
+		{name: "suffix", value: "$(FILE_PATH:suffix)", want: ".gz"},
+		{name: "standardizepath", value: "$(FILE_PATH:standardizepath)", want: "/tmp/MyFile.tar.gz"},
+		{name: "default of empty value", value: "$(EMPTY:default=fallback)", want: "fallback"},
+		{name: "default of undefined value", value: "$(UNDEFINED:default=fallback)", want: "fallback"},
+		{name: "default of defined value", value: "$(CONFIGURATION:default=fallback)", want: "Debug"},
+		{name: "default with reference", value: "$(DEFAULT_IN_REFERENCE)", want: "Debug"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandBuildSetting(tt.value, buildSettings)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpandBuildSetting_Errors(t *testing.T) {
+	t.Log("reference cycle")
+	{
+		buildSettings := serialized.Object{
+			"PRODUCT_NAME": "$(BUNDLE_ID:rfc1034identifier)",
+			"BUNDLE_ID":    "com.bitrise.$(BUNDLE_NAME)",
+			"BUNDLE_NAME":  "$(PRODUCT_NAME)",
+		}
+		_, err := ExpandBuildSetting("prefix.$(BUNDLE_ID)", buildSettings)
+		require.True(t, IsBuildSettingCycleError(err))
+		require.EqualError(t, err, "build setting reference cycle found: BUNDLE_ID -> BUNDLE_NAME -> PRODUCT_NAME -> BUNDLE_ID")
+	}
+
+	t.Log("self reference")
+	{
+		_, err := ExpandBuildSetting("$(NAME)", serialized.Object{"NAME": "$(NAME)"})
+		require.EqualError(t, err, "build setting reference cycle found: NAME -> NAME")
+	}
+
+	t.Log("unknown operator")
+	{
+		_, err := ExpandBuildSetting("$(NAME:unknown)", serialized.Object{"NAME": "value"})
+		require.EqualError(t, err, "failed to expand $(NAME:unknown): unknown build setting operator: unknown")
+	}
+}
@@ -0,0 +1,279 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeConflict is a property changed differently by both sides of a merge.
+// The merged project keeps ours' value of the conflicting properties.
+type MergeConflict struct {
+	// ObjectID is empty for the top level properties of the project.
+	ObjectID string
+	// KeyPath is the dot separated path of the property in the object, empty if the whole object conflicts
+	// (like an object removed by one side and modified by the other).
+	KeyPath string
+	// Base, Ours and Theirs are the conflicting values, nil if the property is missing on the given side.
+	Base, Ours, Theirs interface{}
+}
+
+// String ...
+func (c MergeConflict) String() string {
+	location := c.ObjectID
+	if c.KeyPath != "" {
+		if location != "" {
+			location += "."
+		}
+		location += c.KeyPath
+	}
+	return fmt.Sprintf("%s: base: %s, ours: %s, theirs: %s", location, mergeConflictValue(c.Base), mergeConflictValue(c.Ours), mergeConflictValue(c.Theirs))
+}
+
+func mergeConflictValue(value interface{}) string {
+	if value == nil {
+		return "<missing>"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Merge does a three-way merge of the projects on object level:
+// the properties changed by one side only are taken from that side,
+// the object ID lists (like a group's children or a build phase's files) changed by both sides are united
+// and the other properties changed differently by both sides are reported as conflicts.
+// The returned project is ours with theirs' changes applied, saving it modifies ours' project.pbxproj in-place.
+func Merge(base, ours, theirs XcodeProj) (XcodeProj, []MergeConflict, error) {
+	m := merger{}
+	merged := m.mergeValue("", nil, deepCopy(map[string]interface{}(base.RawProj)), deepCopy(map[string]interface{}(ours.RawProj)), deepCopy(map[string]interface{}(theirs.RawProj)))
+
+	result := ours
+	result.RawProj = merged.(map[string]interface{})
+	if err := result.reloadProj(); err != nil {
+		return XcodeProj{}, nil, err
+	}
+
+	sort.SliceStable(m.conflicts, func(i, j int) bool {
+		if m.conflicts[i].ObjectID != m.conflicts[j].ObjectID {
+			return m.conflicts[i].ObjectID < m.conflicts[j].ObjectID
+		}
+		return m.conflicts[i].KeyPath < m.conflicts[j].KeyPath
+	})
+	return result, m.conflicts, nil
+}
+
+// MergePBXProj merges the base, ours and theirs project.pbxproj contents (see Merge)
+// and returns the merged contents in ours' formatting.
+// The projectName is used to annotate the build configuration list of the project.
+func MergePBXProj(base, ours, theirs []byte, projectName string) ([]byte, []MergeConflict, error) {
+	var projects []XcodeProj
+	for _, side := range []struct {
+		name    string
+		content []byte
+	}{
+		{"base", base},
+		{"ours", ours},
+		{"theirs", theirs},
+	} {
+		proj, err := parsePBXProjContent(side.content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %s", side.name, err)
+		}
+		proj.Name = projectName
+		projects = append(projects, *proj)
+	}
+
+	merged, conflicts, err := Merge(projects[0], projects[1], projects[2])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := merged.pbxProjContents()
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, conflicts, nil
+}
+
+// missingValue stands for a property missing on one side of the merge.
+type missingValue struct{}
+
+type merger struct {
+	conflicts []MergeConflict
+}
+
+// mergeValue returns the merged value of the property at the key path, missingValue{} if the property is removed.
+func (m *merger) mergeValue(objectID string, keyPath []string, base, ours, theirs interface{}) interface{} {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours
+	case reflect.DeepEqual(base, ours):
+		return theirs
+	case reflect.DeepEqual(base, theirs):
+		return ours
+	}
+
+	oursMap, oursIsMap := ours.(map[string]interface{})
+	theirsMap, theirsIsMap := theirs.(map[string]interface{})
+	if oursIsMap && theirsIsMap {
+		baseMap, ok := base.(map[string]interface{})
+		if !ok {
+			// added by both sides
+			baseMap = map[string]interface{}{}
+		}
+		return m.mergeMap(objectID, keyPath, baseMap, oursMap, theirsMap)
+	}
+
+	if objectID != "" && len(keyPath) == 1 && isReferenceListKey(keyPath[0]) {
+		if merged, ok := mergeIDList(base, ours, theirs); ok {
+			return merged
+		}
+	}
+
+	m.conflicts = append(m.conflicts, MergeConflict{
+		ObjectID: objectID,
+		KeyPath:  strings.Join(keyPath, "."),
+		Base:     conflictValue(base),
+		Ours:     conflictValue(ours),
+		Theirs:   conflictValue(theirs),
+	})
+	return ours
+}
+
+func (m *merger) mergeMap(objectID string, keyPath []string, base, ours, theirs map[string]interface{}) map[string]interface{} {
+	keys := map[string]bool{}
+	for _, values := range []map[string]interface{}{base, ours, theirs} {
+		for key := range values {
+			keys[key] = true
+		}
+	}
+
+	merged := map[string]interface{}{}
+	for key := range keys {
+		if key == customAnnotationKey {
+			// the position of ours' contents
+			if value, ok := ours[key]; ok {
+				merged[key] = value
+			}
+			continue
+		}
+
+		childObjectID, childKeyPath := objectID, append(append([]string{}, keyPath...), key)
+		if objectID == "" && len(keyPath) == 1 && keyPath[0] == "objects" {
+			childObjectID, childKeyPath = key, nil
+		}
+
+		value := m.mergeValue(childObjectID, childKeyPath, mapValue(base, key), mapValue(ours, key), mapValue(theirs, key))
+		if _, ok := value.(missingValue); !ok {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func mapValue(values map[string]interface{}, key string) interface{} {
+	if value, ok := values[key]; ok {
+		return value
+	}
+	return missingValue{}
+}
+
+func conflictValue(value interface{}) interface{} {
+	if _, ok := value.(missingValue); ok {
+		return nil
+	}
+	return value
+}
+
+func isReferenceListKey(key string) bool {
+	for _, listKey := range referenceListKeys {
+		if key == listKey {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeIDList unites the object ID lists changed by both sides: the IDs removed by theirs are removed from ours
+// and the IDs added by theirs are inserted after their preceding ID in theirs (and after ours' IDs added to the same position).
+func mergeIDList(base, ours, theirs interface{}) ([]interface{}, bool) {
+	baseIDs, ok := idList(base)
+	if !ok {
+		return nil, false
+	}
+	oursIDs, ok := idList(ours)
+	if !ok {
+		return nil, false
+	}
+	theirsIDs, ok := idList(theirs)
+	if !ok {
+		return nil, false
+	}
+
+	inBase := map[string]bool{}
+	for _, id := range baseIDs {
+		inBase[id] = true
+	}
+	inTheirs := map[string]bool{}
+	for _, id := range theirsIDs {
+		inTheirs[id] = true
+	}
+
+	var merged []string
+	for _, id := range oursIDs {
+		if inBase[id] && !inTheirs[id] {
+			continue
+		}
+		merged = append(merged, id)
+	}
+
+	for i, id := range theirsIDs {
+		if inBase[id] || indexOf(merged, id) != -1 {
+			continue
+		}
+
+		pos := len(merged)
+		if i == 0 {
+			pos = 0
+		} else if previous := indexOf(merged, theirsIDs[i-1]); previous != -1 {
+			pos = previous + 1
+		}
+		// ours' additions at the same position come first
+		for pos < len(merged) && !inBase[merged[pos]] && !inTheirs[merged[pos]] {
+			pos++
+		}
+		merged = append(merged[:pos], append([]string{id}, merged[pos:]...)...)
+	}
+
+	return rawStringSlice(merged), true
+}
+
+// idList returns the IDs of the list, a missing list is an empty list.
+func idList(value interface{}) ([]string, bool) {
+	if _, ok := value.(missingValue); ok {
+		return nil, true
+	}
+	elements, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	var ids []string
+	for _, element := range elements {
+		id, ok := element.(string)
+		if !ok {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+func indexOf(ids []string, id string) int {
+	for i, element := range ids {
+		if element == id {
+			return i
+		}
+	}
+	return -1
+}
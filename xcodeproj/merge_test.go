@@ -0,0 +1,155 @@
+package xcodeproj
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePBXProj(t *testing.T) {
+	const groupID = "7D5B35FE20E28EE80022BAE6"
+
+	edit := func(t *testing.T, seed int64, fileName, buildSetting, bundleID string, modify func(proj *XcodeProj)) (string, string, []byte) {
+		proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+		require.NoError(t, err)
+		proj.Name = "XcodeProj"
+		proj.IDGenerator = NewSeededObjectIDGenerator(seed)
+
+		fileID, err := proj.AddFileReference(groupID, FileReference{FileElementBase: FileElementBase{Path: fileName}})
+		require.NoError(t, err)
+		buildFileIDs, err := proj.AddFileToTargets(fileID, SourcesBuildPhaseType, "XcodeProj")
+		require.NoError(t, err)
+
+		target, ok := proj.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+		debug, ok := target.BuildConfigurationList.BuildConfiguration("Debug")
+		require.True(t, ok)
+		objects, err := proj.RawProj.Object("objects")
+		require.NoError(t, err)
+		buildConfiguration, err := objects.Object(debug.ID)
+		require.NoError(t, err)
+		buildSettings, err := buildConfiguration.Object("buildSettings")
+		require.NoError(t, err)
+		buildSettings[buildSetting] = "YES"
+		buildSettings["PRODUCT_BUNDLE_IDENTIFIER"] = bundleID
+
+		if modify != nil {
+			modify(proj)
+		}
+
+		content, err := proj.perObjectModify()
+		require.NoError(t, err)
+		return fileID, buildFileIDs[0], content
+	}
+
+	oursFileID, oursBuildFileID, ours := edit(t, 1, "Ours.swift", "OURS_SETTING", "io.bitrise.ours", nil)
+	theirsFileID, theirsBuildFileID, theirs := edit(t, 2, "Theirs.swift", "THEIRS_SETTING", "io.bitrise.theirs", func(proj *XcodeProj) {
+		require.NoError(t, proj.RemoveFile("7D5B360B20E28EEA0022BAE6"))
+	})
+
+	content, conflicts, err := MergePBXProj([]byte(testhelper.XcodeProjectTest), ours, theirs, "XcodeProj")
+	require.NoError(t, err)
+
+	proj, err := parsePBXProjContent(content)
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	debug, ok := target.BuildConfigurationList.BuildConfiguration("Debug")
+	require.True(t, ok)
+
+	require.Equal(t, 1, len(conflicts))
+	require.Equal(t, debug.ID, conflicts[0].ObjectID)
+	require.Equal(t, "buildSettings.PRODUCT_BUNDLE_IDENTIFIER", conflicts[0].KeyPath)
+	require.Equal(t, "com.bitrise.XcodeProj", conflicts[0].Base)
+	require.Equal(t, "io.bitrise.ours", conflicts[0].Ours)
+	require.Equal(t, "io.bitrise.theirs", conflicts[0].Theirs)
+
+	require.Equal(t, "io.bitrise.ours", debug.BuildSettings["PRODUCT_BUNDLE_IDENTIFIER"])
+	require.Equal(t, "YES", debug.BuildSettings["OURS_SETTING"])
+	require.Equal(t, "YES", debug.BuildSettings["THEIRS_SETTING"])
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	group, err := objects.Object(groupID)
+	require.NoError(t, err)
+	children, err := group.StringSlice("children")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"7D5B35FF20E28EE80022BAE6",
+		"7D5B360120E28EE80022BAE6",
+		"7D5B360320E28EE80022BAE6",
+		"7D5B360620E28EEA0022BAE6",
+		"7D5B360820E28EEA0022BAE6",
+		oursFileID,
+		theirsFileID,
+	}, children)
+	_, err = objects.Object("7D5B360B20E28EEA0022BAE6")
+	require.Error(t, err)
+
+	var sourcesFileIDs []string
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type == SourcesBuildPhaseType {
+			for _, file := range buildPhase.Files {
+				sourcesFileIDs = append(sourcesFileIDs, file.ID)
+			}
+		}
+	}
+	require.Equal(t, []string{"7D5B360220E28EE80022BAE6", "7D5B360020E28EE80022BAE6", oursBuildFileID, theirsBuildFileID}, sourcesFileIDs)
+
+	issues, err := proj.Validate()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(issues))
+
+	// the unchanged objects keep ours' formatting
+	require.Contains(t, string(content), strings.Split(testhelper.XcodeProjectTest, "\n")[18]+"\n")
+}
+
+func TestMergeIDList(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   interface{}
+		ours   interface{}
+		theirs interface{}
+		want   []interface{}
+	}{
+		{
+			name:   "added by both sides",
+			base:   rawStringSlice([]string{"A", "B"}),
+			ours:   rawStringSlice([]string{"A", "O", "B"}),
+			theirs: rawStringSlice([]string{"A", "B", "T"}),
+			want:   rawStringSlice([]string{"A", "O", "B", "T"}),
+		},
+		{
+			name:   "removed by theirs, added by ours",
+			base:   rawStringSlice([]string{"A", "B"}),
+			ours:   rawStringSlice([]string{"O", "A", "B"}),
+			theirs: rawStringSlice([]string{"B"}),
+			want:   rawStringSlice([]string{"O", "B"}),
+		},
+		{
+			name:   "added to the start by theirs",
+			base:   rawStringSlice([]string{"A"}),
+			ours:   rawStringSlice([]string{"A", "O"}),
+			theirs: rawStringSlice([]string{"T", "A"}),
+			want:   rawStringSlice([]string{"T", "A", "O"}),
+		},
+		{
+			name:   "missing base",
+			base:   missingValue{},
+			ours:   rawStringSlice([]string{"O"}),
+			theirs: rawStringSlice([]string{"T"}),
+			want:   rawStringSlice([]string{"O", "T"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mergeIDList(tt.base, tt.ours, tt.theirs)
+			require.True(t, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package xcodeproj
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// ObjectIDGenerator generates 24 character hexadecimal object IDs (like Xcode's), for the objects added to a project.
+// The generated IDs are unique within the project and the generator: an ID is never returned twice.
+// A generator remembers every ID it returned, use one generator per project.
+type ObjectIDGenerator struct {
+	mu     sync.Mutex
+	rand   *rand.Rand
+	issued map[string]bool
+}
+
+// NewObjectIDGenerator returns a randomly seeded generator.
+func NewObjectIDGenerator() *ObjectIDGenerator {
+	var seed int64
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err == nil {
+		seed = int64(binary.LittleEndian.Uint64(b[:]))
+	}
+	return NewSeededObjectIDGenerator(seed)
+}
+
+// NewSeededObjectIDGenerator returns a generator, which generates the same IDs for the same seed and project.
+func NewSeededObjectIDGenerator(seed int64) *ObjectIDGenerator {
+	return &ObjectIDGenerator{
+		rand:   rand.New(rand.NewSource(seed)),
+		issued: map[string]bool{},
+	}
+}
+
+// Generate returns a new object ID, which is not a key of the objects.
+func (g *ObjectIDGenerator) Generate(objects serialized.Object) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("%08X%08X%08X", g.rand.Uint32(), g.rand.Uint32(), g.rand.Uint32())
+		if _, ok := objects[id]; ok || g.issued[id] {
+			continue
+		}
+
+		g.issued[id] = true
+		return id, nil
+	}
+	return "", fmt.Errorf("failed to generate unique object ID")
+}
+
+// NewObjectID returns a new object ID, which is unique within the project.
+// The project's IDGenerator is used, it is set to a randomly seeded generator if nil.
+func (p *XcodeProj) NewObjectID() (string, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+
+	if p.IDGenerator == nil {
+		p.IDGenerator = NewObjectIDGenerator()
+	}
+	return p.IDGenerator.Generate(objects)
+}
@@ -0,0 +1,126 @@
+package xcodeproj
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// PBXObject is a typed project object, which can be inserted into the project's objects.
+type PBXObject interface {
+	// RawObject returns the object's properties (including the isa) in the RawProj format.
+	RawObject() (map[string]interface{}, error)
+}
+
+// InsertObject adds the object to the project's objects and returns its new ID.
+// The object is not referenced by any other object, the caller needs to link it (like adding a build phase's ID to a target's buildPhases).
+func (p *XcodeProj) InsertObject(object PBXObject) (string, error) {
+	raw, err := object.RawObject()
+	if err != nil {
+		return "", err
+	}
+	if isa, ok := raw["isa"].(string); !ok || isa == "" {
+		return "", fmt.Errorf("object has no isa")
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+
+	id, err := p.NewObjectID()
+	if err != nil {
+		return "", err
+	}
+	objects[id] = raw
+
+	return id, nil
+}
+
+// ObjectsByISA returns the objects of the given isa (like PBXNativeTarget) by ID.
+func (p XcodeProj) ObjectsByISA(isa string) (map[string]serialized.Object, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	objectsByID := map[string]serialized.Object{}
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, err
+		}
+		if objectISA, err := object.String("isa"); err == nil && objectISA == isa {
+			objectsByID[id] = object
+		}
+	}
+	return objectsByID, nil
+}
+
+// ObjectReference is a reference to an object ID from a property of another object.
+type ObjectReference struct {
+	// ID is the ID of the referencing object.
+	ID string
+	// KeyPath is the dot separated path of the referencing property, like: buildPhases or attributes.TargetAttributes.
+	KeyPath string
+}
+
+// ReferencesTo returns every reference to the object ID from the other objects, ordered by the referencing object's ID.
+// Both the property values and the dictionary keys (like the target IDs in the TargetAttributes) count as references.
+func (p XcodeProj) ReferencesTo(id string) ([]ObjectReference, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	var references []ObjectReference
+	for _, objectID := range sortedKeys(objects) {
+		if objectID == id {
+			continue
+		}
+
+		object, err := objects.Object(objectID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, keyPath := range referencingKeyPaths(object, id, "") {
+			references = append(references, ObjectReference{ID: objectID, KeyPath: keyPath})
+		}
+	}
+	return references, nil
+}
+
+func referencingKeyPaths(value interface{}, id string, keyPath string) []string {
+	var keyPaths []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			childKeyPath := key
+			if keyPath != "" {
+				childKeyPath = keyPath + "." + key
+			}
+
+			if key == id {
+				keyPaths = append(keyPaths, keyPath)
+			}
+			keyPaths = append(keyPaths, referencingKeyPaths(v[key], id, childKeyPath)...)
+		}
+	case serialized.Object:
+		return referencingKeyPaths(map[string]interface{}(v), id, keyPath)
+	case []interface{}:
+		for _, element := range v {
+			keyPaths = append(keyPaths, referencingKeyPaths(element, id, keyPath)...)
+		}
+	case []string:
+		for _, element := range v {
+			keyPaths = append(keyPaths, referencingKeyPaths(element, id, keyPath)...)
+		}
+	case string:
+		if v == id {
+			keyPaths = append(keyPaths, keyPath)
+		}
+	}
+
+	return keyPaths
+}
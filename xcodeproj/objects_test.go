@@ -0,0 +1,115 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectIDGenerator_Generate(t *testing.T) {
+	t.Log("seeded generators generate the same IDs")
+	{
+		first, err := NewSeededObjectIDGenerator(1).Generate(nil)
+		require.NoError(t, err)
+		second, err := NewSeededObjectIDGenerator(1).Generate(nil)
+		require.NoError(t, err)
+
+		require.Equal(t, "9ACB0442F0C5341EAA209B8E", first)
+		require.Equal(t, first, second)
+	}
+
+	t.Log("existing and already generated IDs are skipped")
+	{
+		objects := serialized.Object{"9ACB0442F0C5341EAA209B8E": map[string]interface{}{"isa": "PBXGroup"}}
+		generator := NewSeededObjectIDGenerator(1)
+
+		ids := map[string]bool{}
+		for i := 0; i < 1000; i++ {
+			id, err := generator.Generate(objects)
+			require.NoError(t, err)
+			require.Regexp(t, "^[0-9A-F]{24}$", id)
+			require.NotContains(t, objects, id)
+			require.False(t, ids[id])
+			ids[id] = true
+		}
+	}
+}
+
+func TestXcodeProj_NewObjectID(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	require.Nil(t, proj.IDGenerator)
+
+	first, err := proj.NewObjectID()
+	require.NoError(t, err)
+	generator := proj.IDGenerator
+	require.NotNil(t, generator)
+
+	second, err := proj.NewObjectID()
+	require.NoError(t, err)
+	require.True(t, generator == proj.IDGenerator)
+	require.NotEqual(t, first, second)
+}
+
+func TestXcodeProj_InsertObject(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.IDGenerator = NewSeededObjectIDGenerator(1)
+
+	id, err := proj.InsertObject(BuildFile{FileRef: "7D5B35FF20E28EE80022BAE6", CompilerFlags: "-w"})
+	require.NoError(t, err)
+	require.Equal(t, "9ACB0442F0C5341EAA209B8E", id)
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	object, err := objects.Object(id)
+	require.NoError(t, err)
+	require.Equal(t, serialized.Object{
+		"isa":      "PBXBuildFile",
+		"fileRef":  "7D5B35FF20E28EE80022BAE6",
+		"settings": map[string]interface{}{"COMPILER_FLAGS": "-w"},
+	}, object)
+
+	buildFiles, err := proj.ObjectsByISA("PBXBuildFile")
+	require.NoError(t, err)
+	require.Equal(t, 12, len(buildFiles))
+	require.Contains(t, buildFiles, id)
+}
+
+func TestXcodeProj_ObjectsByISA(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	targets, err := proj.ObjectsByISA("PBXNativeTarget")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(targets))
+	require.Equal(t, "XcodeProj", targets["7D5B35FB20E28EE80022BAE6"]["name"])
+
+	targets, err = proj.ObjectsByISA("PBXAggregateTarget")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(targets))
+}
+
+func TestXcodeProj_ReferencesTo(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	references, err := proj.ReferencesTo("7D5B35FB20E28EE80022BAE6")
+	require.NoError(t, err)
+	require.Equal(t, []ObjectReference{
+		{ID: "7D0342F620F4BA280050B6A6", KeyPath: "remoteGlobalIDString"},
+		{ID: "7D0342F720F4BA280050B6A6", KeyPath: "target"},
+		{ID: "7D5B35F420E28EE80022BAE6", KeyPath: "attributes.TargetAttributes.7D0342F020F4BA280050B6A6.TestTargetID"},
+		{ID: "7D5B35F420E28EE80022BAE6", KeyPath: "attributes.TargetAttributes"},
+		{ID: "7D5B35F420E28EE80022BAE6", KeyPath: "targets"},
+	}, references)
+
+	references, err = proj.ReferencesTo("7D5B35FF20E28EE80022BAE6")
+	require.NoError(t, err)
+	require.Equal(t, []ObjectReference{
+		{ID: "7D5B35FE20E28EE80022BAE6", KeyPath: "children"},
+		{ID: "7D5B360020E28EE80022BAE6", KeyPath: "fileRef"},
+	}, references)
+}
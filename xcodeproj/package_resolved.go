@@ -0,0 +1,159 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/swiftpm"
+)
+
+// PackageResolvedMismatch is a remote package of the project, which pin in the Package.resolved does not satisfy the package's requirement.
+type PackageResolvedMismatch struct {
+	Package SwiftPackageReference
+	// Pin is nil if the package is not pinned.
+	Pin    *swiftpm.Pin
+	Reason string
+}
+
+// String ...
+func (m PackageResolvedMismatch) String() string {
+	return fmt.Sprintf("%s: %s", m.Package.RepositoryURL, m.Reason)
+}
+
+// IsSatisfiedBy reports whether the pinned state satisfies the requirement.
+func (r SwiftPackageRequirement) IsSatisfiedBy(state swiftpm.PinState) (bool, error) {
+	switch r.Kind {
+	case BranchRequirementKind:
+		return state.Branch == r.Branch, nil
+	case RevisionRequirementKind:
+		return state.Revision == r.Revision, nil
+	}
+
+	if state.Version == "" {
+		return false, nil
+	}
+	version, err := swiftpm.ParseVersion(state.Version)
+	if err != nil {
+		return false, err
+	}
+
+	switch r.Kind {
+	case ExactVersionRequirementKind:
+		exact, err := swiftpm.ParseVersion(r.Version)
+		if err != nil {
+			return false, err
+		}
+		return version.Compare(exact) == 0, nil
+	case UpToNextMajorVersionRequirementKind, UpToNextMinorVersionRequirementKind, VersionRangeRequirementKind:
+		minimum, err := swiftpm.ParseVersion(r.MinimumVersion)
+		if err != nil {
+			return false, err
+		}
+
+		var maximum swiftpm.Version
+		switch r.Kind {
+		case UpToNextMajorVersionRequirementKind:
+			maximum = swiftpm.Version{Major: minimum.Major + 1}
+		case UpToNextMinorVersionRequirementKind:
+			maximum = swiftpm.Version{Major: minimum.Major, Minor: minimum.Minor + 1}
+		default:
+			if maximum, err = swiftpm.ParseVersion(r.MaximumVersion); err != nil {
+				return false, err
+			}
+		}
+
+		return version.Compare(minimum) >= 0 && version.Compare(maximum) < 0, nil
+	default:
+		return false, fmt.Errorf("unknown Swift package requirement kind: %s", r.Kind)
+	}
+}
+
+// CheckPackageResolved returns the remote packages, which are not pinned in the Package.resolved
+// or which pin does not satisfy the package's requirement.
+func CheckPackageResolved(references []SwiftPackageReference, resolved swiftpm.PackageResolved) ([]PackageResolvedMismatch, error) {
+	var mismatches []PackageResolvedMismatch
+	for _, reference := range references {
+		if reference.Type != RemoteSwiftPackageReferenceType {
+			continue
+		}
+
+		pin, ok := resolved.Pin(reference.RepositoryURL)
+		if !ok {
+			mismatches = append(mismatches, PackageResolvedMismatch{Package: reference, Reason: "package is not pinned"})
+			continue
+		}
+
+		satisfied, err := reference.Requirement.IsSatisfiedBy(pin.State)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check pin of package (%s): %s", reference.RepositoryURL, err)
+		}
+		if !satisfied {
+			mismatches = append(mismatches, PackageResolvedMismatch{
+				Package: reference,
+				Pin:     &pin,
+				Reason:  fmt.Sprintf("pinned %s does not satisfy the requirement %s", pinStateDescription(pin.State), requirementDescription(reference.Requirement)),
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+func pinStateDescription(state swiftpm.PinState) string {
+	switch {
+	case state.Version != "":
+		return "version " + state.Version
+	case state.Branch != "":
+		return "branch " + state.Branch
+	default:
+		return "revision " + state.Revision
+	}
+}
+
+func requirementDescription(r SwiftPackageRequirement) string {
+	switch r.Kind {
+	case UpToNextMajorVersionRequirementKind, UpToNextMinorVersionRequirementKind:
+		return fmt.Sprintf("%s from %s", r.Kind, r.MinimumVersion)
+	case VersionRangeRequirementKind:
+		return fmt.Sprintf("%s %s..<%s", r.Kind, r.MinimumVersion, r.MaximumVersion)
+	case ExactVersionRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.Version)
+	case BranchRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.Branch)
+	case RevisionRequirementKind:
+		return fmt.Sprintf("%s %s", r.Kind, r.Revision)
+	default:
+		return string(r.Kind)
+	}
+}
+
+// PackageResolvedPath returns the path of the project's Package.resolved file.
+func (p XcodeProj) PackageResolvedPath() string {
+	return filepath.Join(p.Path, "project.xcworkspace", "xcshareddata", "swiftpm", "Package.resolved")
+}
+
+// PackageResolved returns the pinned Swift package versions of the project.
+func (p XcodeProj) PackageResolved() (swiftpm.PackageResolved, error) {
+	return swiftpm.OpenPackageResolved(p.PackageResolvedPath())
+}
+
+// CheckPackageResolved returns the remote packages of the project, which are not pinned in the project's Package.resolved
+// or which pin does not satisfy the package's requirement.
+// Every remote package is reported if the project has no Package.resolved.
+func (p XcodeProj) CheckPackageResolved() ([]PackageResolvedMismatch, error) {
+	references := p.Proj.SwiftPackageReferences()
+	if len(references) == 0 {
+		return nil, nil
+	}
+
+	var resolved swiftpm.PackageResolved
+	if exist, err := pathutil.IsPathExists(p.PackageResolvedPath()); err != nil {
+		return nil, err
+	} else if exist {
+		if resolved, err = p.PackageResolved(); err != nil {
+			return nil, err
+		}
+	}
+
+	return CheckPackageResolved(references, resolved)
+}
@@ -0,0 +1,122 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/swiftpm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwiftPackageRequirement_IsSatisfiedBy(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement SwiftPackageRequirement
+		state       swiftpm.PinState
+		want        bool
+	}{
+		{
+			name:        "up to next major",
+			requirement: SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.8.0"},
+			state:       swiftpm.PinState{Version: "5.10.2"},
+			want:        true,
+		},
+		{
+			name:        "up to next major, next major pinned",
+			requirement: SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.8.0"},
+			state:       swiftpm.PinState{Version: "6.0.0"},
+			want:        false,
+		},
+		{
+			name:        "up to next major, lower version pinned",
+			requirement: SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.8.0"},
+			state:       swiftpm.PinState{Version: "5.7.9"},
+			want:        false,
+		},
+		{
+			name:        "up to next minor",
+			requirement: SwiftPackageRequirement{Kind: UpToNextMinorVersionRequirementKind, MinimumVersion: "1.4.2"},
+			state:       swiftpm.PinState{Version: "1.5.0"},
+			want:        false,
+		},
+		{
+			name:        "version range",
+			requirement: SwiftPackageRequirement{Kind: VersionRangeRequirementKind, MinimumVersion: "10.0.0", MaximumVersion: "11.0.0"},
+			state:       swiftpm.PinState{Version: "10.19.1"},
+			want:        true,
+		},
+		{
+			name:        "exact version",
+			requirement: SwiftPackageRequirement{Kind: ExactVersionRequirementKind, Version: "1.2.3"},
+			state:       swiftpm.PinState{Version: "1.2.4"},
+			want:        false,
+		},
+		{
+			name:        "version requirement, branch pinned",
+			requirement: SwiftPackageRequirement{Kind: ExactVersionRequirementKind, Version: "1.2.3"},
+			state:       swiftpm.PinState{Branch: "main", Revision: "532d8b5"},
+			want:        false,
+		},
+		{
+			name:        "branch",
+			requirement: SwiftPackageRequirement{Kind: BranchRequirementKind, Branch: "main"},
+			state:       swiftpm.PinState{Branch: "main", Revision: "532d8b5"},
+			want:        true,
+		},
+		{
+			name:        "revision",
+			requirement: SwiftPackageRequirement{Kind: RevisionRequirementKind, Revision: "532d8b5"},
+			state:       swiftpm.PinState{Revision: "3dc6a42"},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.requirement.IsSatisfiedBy(tt.state)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckPackageResolved(t *testing.T) {
+	alamofire := SwiftPackageReference{
+		Type:          RemoteSwiftPackageReferenceType,
+		ID:            "4F0A5A3B2A6E6C2E00B6C2A1",
+		RepositoryURL: "https://github.com/Alamofire/Alamofire.git",
+		Requirement:   SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.9.0"},
+	}
+	swiftLog := SwiftPackageReference{
+		Type:          RemoteSwiftPackageReferenceType,
+		ID:            "4F0A5A3C2A6E6C2E00B6C2A1",
+		RepositoryURL: "https://github.com/apple/swift-log",
+		Requirement:   SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "1.5.0"},
+	}
+	firebase := SwiftPackageReference{
+		Type:          RemoteSwiftPackageReferenceType,
+		ID:            "4F0A5A3D2A6E6C2E00B6C2A1",
+		RepositoryURL: "https://github.com/firebase/firebase-ios-sdk",
+		Requirement:   SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "10.0.0"},
+	}
+	local := SwiftPackageReference{
+		Type:         LocalSwiftPackageReferenceType,
+		ID:           "4F0A5A3E2A6E6C2E00B6C2A1",
+		RelativePath: "../Packages/Core",
+	}
+
+	alamofirePin := swiftpm.Pin{Identity: "alamofire", Location: "https://github.com/Alamofire/Alamofire.git", State: swiftpm.PinState{Version: "5.8.1"}}
+	resolved := swiftpm.PackageResolved{
+		Version: 2,
+		Pins: []swiftpm.Pin{
+			alamofirePin,
+			{Identity: "swift-log", Location: "https://github.com/apple/swift-log.git", State: swiftpm.PinState{Version: "1.5.3"}},
+		},
+	}
+
+	mismatches, err := CheckPackageResolved([]SwiftPackageReference{alamofire, swiftLog, firebase, local}, resolved)
+	require.NoError(t, err)
+	require.Equal(t, []PackageResolvedMismatch{
+		{Package: alamofire, Pin: &alamofirePin, Reason: "pinned version 5.8.1 does not satisfy the requirement upToNextMajorVersion from 5.9.0"},
+		{Package: firebase, Reason: "package is not pinned"},
+	}, mismatches)
+	require.Equal(t, "https://github.com/firebase/firebase-ios-sdk: package is not pinned", mismatches[1].String())
+}
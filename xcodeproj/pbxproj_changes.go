@@ -0,0 +1,346 @@
+package xcodeproj
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// objectSection is a `/* Begin ISA section */ ... /* End ISA section */` block of the pbxproj contents.
+type objectSection struct {
+	isa string
+	// start is the start of the Begin line, end is the end of the End line (including the line break).
+	start, end int
+	// bodyEnd is the start of the End line.
+	bodyEnd int
+}
+
+var objectSectionBeginRegexp = regexp.MustCompile(`(?m)^/\* Begin (\w+) section \*/\n`)
+
+// findObjectSections returns the object sections of the pbxproj contents in order of appearance.
+func findObjectSections(contents []byte) ([]objectSection, error) {
+	var sections []objectSection
+	for _, match := range objectSectionBeginRegexp.FindAllSubmatchIndex(contents, -1) {
+		isa := string(contents[match[2]:match[3]])
+
+		endLine := []byte(fmt.Sprintf("/* End %s section */", isa))
+		endIdx := bytes.Index(contents[match[1]:], endLine)
+		if endIdx == -1 {
+			return nil, fmt.Errorf("no end of %s section found", isa)
+		}
+		bodyEnd := match[1] + endIdx
+
+		end := bodyEnd + len(endLine)
+		if end < len(contents) && contents[end] == '\n' {
+			end++
+		}
+
+		sections = append(sections, objectSection{isa: isa, start: match[0], end: end, bodyEnd: bodyEnd})
+	}
+	return sections, nil
+}
+
+func objectSectionByISA(sections []objectSection, isa string) (objectSection, bool) {
+	for _, section := range sections {
+		if section.isa == isa {
+			return section, true
+		}
+	}
+	return objectSection{}, false
+}
+
+// objectPosition returns the position of the object's `{...}` in the original pbxproj contents.
+func objectPosition(objectsAnnotated serialized.Object, id string) (int, int, error) {
+	objectAnnotated, err := objectsAnnotated.Object(id)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	customPosDict, err := objectAnnotated.Object(customAnnotationKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no raw object position available: %v", err)
+	}
+	startPos, err := customPosDict.Int64(startKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no raw object start position available: %v", err)
+	}
+	endPos, err := customPosDict.Int64(endKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no raw end position availbale: %v", err)
+	}
+	return int(startPos), int(endPos), nil
+}
+
+// objectLineRange returns the range of the lines holding the object's `ID /* comment */ = {...};` entry.
+func objectLineRange(contents []byte, objectsAnnotated serialized.Object, id string) (int, int, error) {
+	startPos, endPos, err := objectPosition(objectsAnnotated, id)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lineStart := bytes.LastIndexByte(contents[:startPos], '\n') + 1
+	lineEnd := len(contents)
+	if idx := bytes.IndexByte(contents[endPos:], '\n'); idx != -1 {
+		lineEnd = endPos + idx + 1
+	}
+
+	prefix := strings.TrimSpace(string(contents[lineStart:startPos]))
+	suffix := strings.TrimSpace(string(contents[endPos:lineEnd]))
+	if !strings.HasPrefix(prefix, id) || !strings.HasSuffix(prefix, "=") || suffix != ";" {
+		return 0, 0, fmt.Errorf("object (%s) does not reside on its own lines", id)
+	}
+
+	return lineStart, lineEnd, nil
+}
+
+func objectISA(objects serialized.Object, id string) (string, error) {
+	object, err := objects.Object(id)
+	if err != nil {
+		return "", err
+	}
+	return object.String("isa")
+}
+
+func sortedObjectIDs(objects serialized.Object, filter func(id string) bool) []string {
+	var ids []string
+	for id := range objects {
+		if filter(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// removedObjectChanges cuts the lines of the removed objects,
+// the sections left without objects are removed together with their preceding empty line.
+// It returns the changes and the removed sections.
+func removedObjectChanges(contents []byte, sections []objectSection, objectsMod, objectsOrig, objectsAnnotated serialized.Object) ([]change, map[string]bool, error) {
+	remainingByISA := map[string]int{}
+	for id := range objectsMod {
+		isa, err := objectISA(objectsMod, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		remainingByISA[isa]++
+	}
+
+	removedIDs := sortedObjectIDs(objectsOrig, func(id string) bool {
+		_, ok := objectsMod[id]
+		return !ok
+	})
+
+	var changes []change
+	removedSections := map[string]bool{}
+	for _, id := range removedIDs {
+		isa, err := objectISA(objectsOrig, id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if section, ok := objectSectionByISA(sections, isa); ok && remainingByISA[isa] == 0 {
+			if removedSections[isa] {
+				continue
+			}
+			removedSections[isa] = true
+
+			start := section.start
+			if start >= 2 && contents[start-1] == '\n' && contents[start-2] == '\n' {
+				start--
+			}
+			changes = append(changes, change{start: start, end: section.end})
+			continue
+		}
+
+		lineStart, lineEnd, err := objectLineRange(contents, objectsAnnotated, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to remove object: %v", err)
+		}
+		changes = append(changes, change{start: lineStart, end: lineEnd})
+	}
+
+	return changes, removedSections, nil
+}
+
+// insertedObjectChanges inserts the new objects into the section of their isa, ordered by object ID.
+// The missing sections are created in alphabetical order.
+func insertedObjectChanges(contents []byte, sections []objectSection, removedSections map[string]bool, objectsMod, objectsOrig, objectsAnnotated serialized.Object, w *pbxProjWriter) ([]change, error) {
+	insertedIDs := sortedObjectIDs(objectsMod, func(id string) bool {
+		_, ok := objectsOrig[id]
+		return !ok
+	})
+	if len(insertedIDs) == 0 {
+		return nil, nil
+	}
+
+	var isas []string
+	insertedIDsByISA := map[string][]string{}
+	for _, id := range insertedIDs {
+		isa, err := objectISA(objectsMod, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := insertedIDsByISA[isa]; !ok {
+			isas = append(isas, isa)
+		}
+		insertedIDsByISA[isa] = append(insertedIDsByISA[isa], id)
+	}
+	sort.Strings(isas)
+
+	var changes []change
+	for _, isa := range isas {
+		ids := insertedIDsByISA[isa]
+
+		section, ok := objectSectionByISA(sections, isa)
+		if !ok {
+			sectionChange, err := newObjectSectionChange(sections, removedSections, isa, ids, w)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, sectionChange)
+			continue
+		}
+
+		existingIDs := sortedObjectIDs(objectsOrig, func(id string) bool {
+			existingISA, err := objectISA(objectsOrig, id)
+			return err == nil && existingISA == isa
+		})
+
+		for _, id := range ids {
+			pos := section.bodyEnd
+			for _, existingID := range existingIDs {
+				if existingID > id {
+					lineStart, _, err := objectLineRange(contents, objectsAnnotated, existingID)
+					if err != nil {
+						return nil, fmt.Errorf("failed to insert object: %v", err)
+					}
+					pos = lineStart
+					break
+				}
+			}
+
+			changes = append(changes, change{start: pos, end: pos, rawObject: w.marshalObjectEntry(id)})
+		}
+	}
+
+	return changes, nil
+}
+
+func newObjectSectionChange(sections []objectSection, removedSections map[string]bool, isa string, ids []string, w *pbxProjWriter) (change, error) {
+	var lastSection *objectSection
+	for i, section := range sections {
+		if removedSections[section.isa] {
+			continue
+		}
+		lastSection = &sections[i]
+	}
+	if lastSection == nil {
+		return change{}, fmt.Errorf("no object section found to insert %s section", isa)
+	}
+
+	var block bytes.Buffer
+	block.WriteString(fmt.Sprintf("/* Begin %s section */\n", isa))
+	for _, id := range ids {
+		block.Write(w.marshalObjectEntry(id))
+	}
+	block.WriteString(fmt.Sprintf("/* End %s section */\n", isa))
+
+	for _, section := range sections {
+		if removedSections[section.isa] || section.isa < isa {
+			continue
+		}
+		return change{start: section.start, end: section.start, rawObject: append(block.Bytes(), '\n')}, nil
+	}
+
+	return change{start: lastSection.end, end: lastSection.end, rawObject: append([]byte("\n"), block.Bytes()...)}, nil
+}
+
+// updateObjectComments updates the `ID /* comment */` annotations of the objects, whose comment changed (like the build files of a renamed file),
+// as the annotations in the unchanged objects are kept as they were in the original contents.
+// Only the comments directly following the object's ID are updated, the quoted strings and the other comments are kept.
+func updateObjectComments(contents []byte, rawProjOrig serialized.Object, w *pbxProjWriter) ([]byte, error) {
+	wOrig, err := newPBXProjWriter(rawProjOrig, w.projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	type commentChange struct {
+		oldAnnotation, newAnnotation string
+	}
+	commentChanges := map[string]commentChange{}
+	for _, id := range sortedKeys(wOrig.objects) {
+		if _, ok := w.objects[id]; !ok {
+			continue
+		}
+
+		oldComment, newComment := wOrig.comment(id), w.comment(id)
+		if oldComment == "" || oldComment == newComment {
+			continue
+		}
+
+		newAnnotation := ""
+		if newComment != "" {
+			newAnnotation = " /* " + newComment + " */"
+		}
+		commentChanges[quotePBXProjString(id)] = commentChange{oldAnnotation: " /* " + oldComment + " */", newAnnotation: newAnnotation}
+	}
+	if len(commentChanges) == 0 {
+		return contents, nil
+	}
+
+	var b bytes.Buffer
+	for i := 0; i < len(contents); {
+		end := pbxProjTokenEnd(contents, i)
+		if end == i {
+			b.WriteByte(contents[i])
+			i++
+			continue
+		}
+
+		token := string(contents[i:end])
+		b.WriteString(token)
+		i = end
+
+		if change, ok := commentChanges[token]; ok && bytes.HasPrefix(contents[i:], []byte(change.oldAnnotation)) {
+			b.WriteString(change.newAnnotation)
+			i += len(change.oldAnnotation)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// pbxProjTokenEnd returns the end of the quoted string, the comment or the unquoted string starting at the position,
+// or the position itself if no token starts there.
+func pbxProjTokenEnd(contents []byte, start int) int {
+	switch {
+	case contents[start] == '"':
+		for i := start + 1; i < len(contents); i++ {
+			switch contents[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return len(contents)
+	case bytes.HasPrefix(contents[start:], []byte("/*")):
+		if idx := bytes.Index(contents[start+2:], []byte("*/")); idx != -1 {
+			return start + 2 + idx + 2
+		}
+		return len(contents)
+	}
+
+	end := start
+	for end < len(contents) && isUnquotedPBXProjStringByte(contents[end]) {
+		end++
+	}
+	return end
+}
+
+func isUnquotedPBXProjStringByte(c byte) bool {
+	return c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte("_$./-:", c) != -1
+}
@@ -0,0 +1,152 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_perObjectModify_InsertAndRemove(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(objects serialized.Object)
+		want       string
+		wantNotHas string
+	}{
+		{
+			name: "insert into existing section in ID order",
+			modify: func(objects serialized.Object) {
+				objects["7D5B360220E28EE80022BAF0"] = map[string]interface{}{"isa": "PBXBuildFile", "fileRef": "7D5B360120E28EE80022BAE6"}
+				objects["7D5B360B20E28EE80022BAE6"] = map[string]interface{}{"isa": "PBXBuildFile", "fileRef": "7D5B35FF20E28EE80022BAE6"}
+			},
+			want: `		7D5B360220E28EE80022BAE6 /* ViewController.swift in Sources */ = {isa = PBXBuildFile; fileRef = 7D5B360120E28EE80022BAE6 /* ViewController.swift */; };
+		7D5B360220E28EE80022BAF0 /* ViewController.swift */ = {isa = PBXBuildFile; fileRef = 7D5B360120E28EE80022BAE6 /* ViewController.swift */; };
+		7D5B360520E28EE80022BAE6 /* Main.storyboard in Resources */ = {isa = PBXBuildFile; fileRef = 7D5B360320E28EE80022BAE6 /* Main.storyboard */; };
+		7D5B360720E28EEA0022BAE6 /* Assets.xcassets in Resources */ = {isa = PBXBuildFile; fileRef = 7D5B360620E28EEA0022BAE6 /* Assets.xcassets */; };
+		7D5B360A20E28EEA0022BAE6 /* LaunchScreen.storyboard in Resources */ = {isa = PBXBuildFile; fileRef = 7D5B360820E28EEA0022BAE6 /* LaunchScreen.storyboard */; };
+		7D5B360B20E28EE80022BAE6 /* AppDelegate.swift */ = {isa = PBXBuildFile; fileRef = 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */; };
+/* End PBXBuildFile section */
+`,
+		},
+		{
+			name: "insert into new section",
+			modify: func(objects serialized.Object) {
+				objects["AA0000000000000000000001"] = map[string]interface{}{"isa": "PBXShellScriptBuildPhase", "shellScript": "echo"}
+			},
+			want: `/* End PBXResourcesBuildPhase section */
+
+/* Begin PBXShellScriptBuildPhase section */
+		AA0000000000000000000001 /* ShellScript */ = {
+			isa = PBXShellScriptBuildPhase;
+			shellScript = echo;
+		};
+/* End PBXShellScriptBuildPhase section */
+
+/* Begin PBXSourcesBuildPhase section */
+`,
+		},
+		{
+			name: "insert into new last section",
+			modify: func(objects serialized.Object) {
+				objects["AA0000000000000000000002"] = map[string]interface{}{"isa": "XCVersionGroup", "children": []interface{}{}}
+			},
+			want: `/* End XCConfigurationList section */
+
+/* Begin XCVersionGroup section */
+		AA0000000000000000000002 = {
+			isa = XCVersionGroup;
+			children = (
+			);
+		};
+/* End XCVersionGroup section */
+	};
+	rootObject = 7D5B35F420E28EE80022BAE6 /* Project object */;
+`,
+		},
+		{
+			name: "remove object",
+			modify: func(objects serialized.Object) {
+				delete(objects, "7D5B360220E28EE80022BAE6")
+			},
+			want: `		7D5B360020E28EE80022BAE6 /* AppDelegate.swift in Sources */ = {isa = PBXBuildFile; fileRef = 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */; };
+		7D5B360520E28EE80022BAE6 /* Main.storyboard in Resources */ = {isa = PBXBuildFile; fileRef = 7D5B360320E28EE80022BAE6 /* Main.storyboard */; };
+`,
+			wantNotHas: "7D5B360220E28EE80022BAE6 /* ViewController.swift in Sources */ = {",
+		},
+		{
+			name: "remove emptied section",
+			modify: func(objects serialized.Object) {
+				delete(objects, "7D03431E20F4BB070050B6A6")
+			},
+			want: `/* End PBXContainerItemProxy section */
+
+/* Begin PBXFileReference section */
+`,
+			wantNotHas: "PBXCopyFilesBuildPhase section",
+		},
+		{
+			name: "insert and remove at the same position",
+			modify: func(objects serialized.Object) {
+				objects["7D5B360220E28EE80022BAF0"] = map[string]interface{}{"isa": "PBXBuildFile", "fileRef": "7D5B360120E28EE80022BAE6"}
+				delete(objects, "7D5B360520E28EE80022BAE6")
+			},
+			want: `		7D5B360220E28EE80022BAF0 /* ViewController.swift */ = {isa = PBXBuildFile; fileRef = 7D5B360120E28EE80022BAE6 /* ViewController.swift */; };
+		7D5B360720E28EEA0022BAE6 /* Assets.xcassets in Resources */ = {isa = PBXBuildFile; fileRef = 7D5B360620E28EEA0022BAE6 /* Assets.xcassets */; };
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+			require.NoError(t, err)
+
+			objects, err := proj.RawProj.Object("objects")
+			require.NoError(t, err)
+			tt.modify(objects)
+
+			got, err := proj.perObjectModify()
+			require.NoError(t, err)
+			require.Contains(t, string(got), tt.want)
+			if tt.wantNotHas != "" {
+				require.NotContains(t, string(got), tt.wantNotHas)
+			}
+
+			reopened, err := parsePBXProjContent(got)
+			require.NoError(t, err)
+			reopenedObjects, err := reopened.RawProj.Object("objects")
+			require.NoError(t, err)
+			require.Equal(t, objects, reopenedObjects)
+		})
+	}
+}
+
+func Test_updateObjectComments(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	fileReference, err := objects.Object("7D5B35FF20E28EE80022BAE6")
+	require.NoError(t, err)
+	fileReference["path"] = "Main.swift"
+
+	w, err := newPBXProjWriter(proj.RawProj, "XcodeProj")
+	require.NoError(t, err)
+
+	contents := `7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */ = {isa = PBXFileReference; path = Main.swift; };
+7D5B360020E28EE80022BAE6 /* AppDelegate.swift in Sources */ = {isa = PBXBuildFile; fileRef = 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */; };
+17D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */ = {isa = PBXFileReference; };
+shellScript = "echo 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */";
+/* 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */
+`
+	got, err := updateObjectComments([]byte(contents), proj.originalPbxProj, w)
+	require.NoError(t, err)
+	require.Equal(t, `7D5B35FF20E28EE80022BAE6 /* Main.swift */ = {isa = PBXFileReference; path = Main.swift; };
+7D5B360020E28EE80022BAE6 /* Main.swift in Sources */ = {isa = PBXBuildFile; fileRef = 7D5B35FF20E28EE80022BAE6 /* Main.swift */; };
+17D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */ = {isa = PBXFileReference; };
+shellScript = "echo 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */";
+/* 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */
+`, string(got))
+}
@@ -0,0 +1,366 @@
+package xcodeproj
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// Marshal returns the project.pbxproj contents of the project (RawProj) in the format Xcode writes it:
+// the objects are grouped to isa sections, the object references are annotated with the object names
+// and the PBXBuildFile and PBXFileReference objects are written on a single line.
+func (p XcodeProj) Marshal() ([]byte, error) {
+	w, err := newPBXProjWriter(p.RawProj, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	return w.marshal(), nil
+}
+
+// pbxProjWriter writes a project.pbxproj in Xcode's OpenStep plist format.
+type pbxProjWriter struct {
+	rawProj     serialized.Object
+	objects     serialized.Object
+	projectName string
+
+	// buildPhaseByFile maps the PBXBuildFile IDs to the ID of the build phase containing them.
+	buildPhaseByFile map[string]string
+	// ownerByConfigurationList maps the XCConfigurationList IDs to the ID of the project or target owning them.
+	ownerByConfigurationList map[string]string
+}
+
+func newPBXProjWriter(rawProj serialized.Object, projectName string) (*pbxProjWriter, error) {
+	objects, err := rawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	w := &pbxProjWriter{
+		rawProj:                  rawProj,
+		objects:                  objects,
+		projectName:              projectName,
+		buildPhaseByFile:         map[string]string{},
+		ownerByConfigurationList: map[string]string{},
+	}
+
+	for _, id := range sortedKeys(objects) {
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object (%s): %s", id, err)
+		}
+
+		files, err := object.StringSlice("files")
+		if err == nil {
+			for _, file := range files {
+				w.buildPhaseByFile[file] = id
+			}
+		}
+
+		if configurationList, err := object.String("buildConfigurationList"); err == nil {
+			w.ownerByConfigurationList[configurationList] = id
+		}
+	}
+
+	return w, nil
+}
+
+func (w pbxProjWriter) marshal() []byte {
+	var b bytes.Buffer
+	b.WriteString("// !$*UTF8*$!\n{\n")
+	for _, key := range sortedKeys(w.rawProj) {
+		if key == customAnnotationKey {
+			continue
+		}
+
+		if key == "objects" {
+			b.WriteString("\tobjects = {\n")
+			w.writeObjectSections(&b)
+			b.WriteString("\t};\n")
+			continue
+		}
+
+		b.WriteString("\t" + quotePBXProjString(key) + " = ")
+		w.writeValue(&b, w.rawProj[key], 1, false, key)
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func (w pbxProjWriter) writeObjectSections(b *bytes.Buffer) {
+	idsByISA := map[string][]string{}
+	for _, id := range sortedKeys(w.objects) {
+		isa := w.isa(id)
+		idsByISA[isa] = append(idsByISA[isa], id)
+	}
+
+	var isas []string
+	for isa := range idsByISA {
+		isas = append(isas, isa)
+	}
+	sort.Strings(isas)
+
+	for _, isa := range isas {
+		b.WriteString(fmt.Sprintf("\n/* Begin %s section */\n", isa))
+		for _, id := range idsByISA[isa] {
+			b.Write(w.marshalObjectEntry(id))
+		}
+		b.WriteString(fmt.Sprintf("/* End %s section */\n", isa))
+	}
+}
+
+// marshalObjectEntry returns the `\t\tID /* comment */ = {...};\n` entry of the object.
+func (w pbxProjWriter) marshalObjectEntry(id string) []byte {
+	var b bytes.Buffer
+	b.WriteString("\t\t")
+	w.writeReference(&b, id)
+	b.WriteString(" = ")
+	b.Write(w.marshalObjectValue(id))
+	b.WriteString(";\n")
+	return b.Bytes()
+}
+
+// marshalObjectValue returns the `{...}` value of the object's entry.
+func (w pbxProjWriter) marshalObjectValue(id string) []byte {
+	var b bytes.Buffer
+	object, _ := w.objects.Object(id)
+	isa := w.isa(id)
+	w.writeObject(&b, object, 2, isa == "PBXBuildFile" || isa == "PBXFileReference")
+	return b.Bytes()
+}
+
+func (w pbxProjWriter) writeObject(b *bytes.Buffer, object map[string]interface{}, indent int, singleLine bool) {
+	keys := sortedKeys(object)
+	if _, ok := object["isa"]; ok {
+		// isa goes first
+		sorted := []string{"isa"}
+		for _, key := range keys {
+			if key != "isa" {
+				sorted = append(sorted, key)
+			}
+		}
+		keys = sorted
+	}
+
+	b.WriteString("{")
+	if !singleLine {
+		b.WriteString("\n")
+	}
+	for _, key := range keys {
+		if key == customAnnotationKey {
+			continue
+		}
+
+		if !singleLine {
+			b.WriteString(strings.Repeat("\t", indent+1))
+		}
+		b.WriteString(quotePBXProjString(key) + " = ")
+		w.writeValue(b, object[key], indent+1, singleLine, key)
+		b.WriteString(";")
+		if singleLine {
+			b.WriteString(" ")
+		} else {
+			b.WriteString("\n")
+		}
+	}
+	if !singleLine {
+		b.WriteString(strings.Repeat("\t", indent))
+	}
+	b.WriteString("}")
+}
+
+func (w pbxProjWriter) writeValue(b *bytes.Buffer, value interface{}, indent int, singleLine bool, key string) {
+	switch v := value.(type) {
+	case serialized.Object:
+		w.writeObject(b, v, indent, singleLine)
+	case map[string]interface{}:
+		w.writeObject(b, v, indent, singleLine)
+	case []string:
+		var elements []interface{}
+		for _, element := range v {
+			elements = append(elements, element)
+		}
+		w.writeArray(b, elements, indent, singleLine, key)
+	case []interface{}:
+		w.writeArray(b, v, indent, singleLine, key)
+	case string:
+		if isUncommentedReferenceKey(key) {
+			b.WriteString(quotePBXProjString(v))
+		} else {
+			w.writeReference(b, v)
+		}
+	case bool:
+		if v {
+			b.WriteString("YES")
+		} else {
+			b.WriteString("NO")
+		}
+	default:
+		b.WriteString(quotePBXProjString(fmt.Sprintf("%v", v)))
+	}
+}
+
+func (w pbxProjWriter) writeArray(b *bytes.Buffer, elements []interface{}, indent int, singleLine bool, key string) {
+	b.WriteString("(")
+	if !singleLine {
+		b.WriteString("\n")
+	}
+	for _, element := range elements {
+		if !singleLine {
+			b.WriteString(strings.Repeat("\t", indent+1))
+		}
+		w.writeValue(b, element, indent+1, singleLine, key)
+		b.WriteString(",")
+		if singleLine {
+			b.WriteString(" ")
+		} else {
+			b.WriteString("\n")
+		}
+	}
+	if !singleLine {
+		b.WriteString(strings.Repeat("\t", indent))
+	}
+	b.WriteString(")")
+}
+
+// writeReference writes the string, if it is an object ID the object's comment is appended: ID /* comment */
+func (w pbxProjWriter) writeReference(b *bytes.Buffer, value string) {
+	b.WriteString(quotePBXProjString(value))
+	if _, ok := w.objects[value]; !ok {
+		return
+	}
+	if comment := w.comment(value); comment != "" {
+		b.WriteString(" /* " + comment + " */")
+	}
+}
+
+// isUncommentedReferenceKey reports whether the object IDs of the given key are written without comment.
+func isUncommentedReferenceKey(key string) bool {
+	return key == "remoteGlobalIDString" || key == "TestTargetID"
+}
+
+func (w pbxProjWriter) isa(id string) string {
+	object, err := w.objects.Object(id)
+	if err != nil {
+		return ""
+	}
+	isa, _ := object.String("isa")
+	return isa
+}
+
+func (w pbxProjWriter) stringValue(id, key string) string {
+	object, err := w.objects.Object(id)
+	if err != nil {
+		return ""
+	}
+	value, _ := object.String(key)
+	return value
+}
+
+// comment returns the name Xcode uses to annotate the object.
+func (w pbxProjWriter) comment(id string) string {
+	isa := w.isa(id)
+	switch isa {
+	case "PBXProject":
+		return "Project object"
+	case "PBXBuildFile":
+		return w.buildFileComment(id)
+	case "PBXContainerItemProxy", "PBXTargetDependency", "PBXBuildRule":
+		return isa
+	case "XCConfigurationList":
+		ownerID := w.ownerByConfigurationList[id]
+		ownerISA := w.isa(ownerID)
+		ownerName := w.stringValue(ownerID, "name")
+		if ownerISA == "PBXProject" {
+			ownerName = w.projectName
+		}
+		if ownerISA == "" {
+			return ""
+		}
+		return fmt.Sprintf("Build configuration list for %s \"%s\"", ownerISA, ownerName)
+	case "XCRemoteSwiftPackageReference":
+		repositoryName := strings.TrimSuffix(path.Base(w.stringValue(id, "repositoryURL")), ".git")
+		return fmt.Sprintf("%s \"%s\"", isa, repositoryName)
+	case "XCLocalSwiftPackageReference":
+		return fmt.Sprintf("%s \"%s\"", isa, w.stringValue(id, "relativePath"))
+	case "XCSwiftPackageProductDependency":
+		return w.stringValue(id, "productName")
+	case "PBXReferenceProxy":
+		if pth := w.stringValue(id, "path"); pth != "" {
+			return pth
+		}
+		return w.stringValue(id, "name")
+	}
+
+	if name := w.stringValue(id, "name"); name != "" {
+		return name
+	}
+	if pth := w.stringValue(id, "path"); pth != "" {
+		return pth
+	}
+	return defaultBuildPhaseName(isa)
+}
+
+// buildFileComment returns the `FileName in BuildPhaseName` comment of the PBXBuildFile.
+func (w pbxProjWriter) buildFileComment(id string) string {
+	fileName := "(null)"
+	if fileRef := w.stringValue(id, "fileRef"); fileRef != "" {
+		fileName = w.comment(fileRef)
+	} else if productRef := w.stringValue(id, "productRef"); productRef != "" {
+		fileName = w.comment(productRef)
+	}
+
+	buildPhaseID, ok := w.buildPhaseByFile[id]
+	if !ok {
+		return fileName
+	}
+	return fmt.Sprintf("%s in %s", fileName, w.comment(buildPhaseID))
+}
+
+func defaultBuildPhaseName(isa string) string {
+	switch isa {
+	case "PBXSourcesBuildPhase":
+		return "Sources"
+	case "PBXFrameworksBuildPhase":
+		return "Frameworks"
+	case "PBXResourcesBuildPhase":
+		return "Resources"
+	case "PBXHeadersBuildPhase":
+		return "Headers"
+	case "PBXCopyFilesBuildPhase":
+		return "CopyFiles"
+	case "PBXShellScriptBuildPhase":
+		return "ShellScript"
+	case "PBXRezBuildPhase":
+		return "Rez"
+	default:
+		return ""
+	}
+}
+
+// quotePBXProjString quotes and escapes the string, unless it consists of letters, digits and `_$./` only.
+// The empty strings and the strings containing `___` or `//` are quoted too.
+func quotePBXProjString(s string) string {
+	if s != "" && !strings.Contains(s, "___") && !strings.Contains(s, "//") && strings.IndexFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsMark(r) || ('0' <= r && r <= '9') || strings.ContainsRune("_$./", r))
+	}) == -1 {
+		return s
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\t", `\t`, "\n", `\n`).Replace(s)
+	return `"` + escaped + `"`
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
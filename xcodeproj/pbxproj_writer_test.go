@@ -0,0 +1,80 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_Marshal(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	got, err := proj.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, testhelper.XcodeProjectTest, string(got))
+}
+
+func Test_quotePBXProjString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: `""`},
+		{value: "XcodeProj/Info.plist", want: "XcodeProj/Info.plist"},
+		{value: "$SRCROOT", want: "$SRCROOT"},
+		{value: "9.4.1", want: "9.4.1"},
+		{value: "Árvíztűrő", want: "Árvíztűrő"},
+		{value: "<group>", want: `"<group>"`},
+		{value: "$(TARGET_NAME)", want: `"$(TARGET_NAME)"`},
+		{value: "wrapper.app-extension", want: `"wrapper.app-extension"`},
+		{value: "Xcode 9.3", want: `"Xcode 9.3"`},
+		{value: "a___b", want: `"a___b"`},
+		{value: "http://a", want: `"http://a"`},
+		{value: "echo \"hello\"\n\tpath\\", want: `"echo \"hello\"\n\tpath\\"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			require.Equal(t, tt.want, quotePBXProjString(tt.value))
+		})
+	}
+}
+
+func Test_pbxProjWriter_comment(t *testing.T) {
+	rawProj := serialized.Object{
+		"objects": map[string]interface{}{
+			"PROJECT":            map[string]interface{}{"isa": "PBXProject", "buildConfigurationList": "PROJECT_CONFIGS", "packageReferences": []interface{}{"REMOTE_PACKAGE", "LOCAL_PACKAGE"}},
+			"PROJECT_CONFIGS":    map[string]interface{}{"isa": "XCConfigurationList"},
+			"TARGET":             map[string]interface{}{"isa": "PBXNativeTarget", "name": "App", "buildConfigurationList": "TARGET_CONFIGS"},
+			"TARGET_CONFIGS":     map[string]interface{}{"isa": "XCConfigurationList"},
+			"SCRIPT_PHASE":       map[string]interface{}{"isa": "PBXShellScriptBuildPhase", "files": []interface{}{"PRODUCT_BUILD_FILE"}},
+			"REMOTE_PACKAGE":     map[string]interface{}{"isa": "XCRemoteSwiftPackageReference", "repositoryURL": "https://github.com/Alamofire/Alamofire.git"},
+			"LOCAL_PACKAGE":      map[string]interface{}{"isa": "XCLocalSwiftPackageReference", "relativePath": "../Packages/Core"},
+			"PRODUCT":            map[string]interface{}{"isa": "XCSwiftPackageProductDependency", "productName": "Alamofire"},
+			"PRODUCT_BUILD_FILE": map[string]interface{}{"isa": "PBXBuildFile", "productRef": "PRODUCT"},
+			"ORPHAN_BUILD_FILE":  map[string]interface{}{"isa": "PBXBuildFile", "fileRef": "FILE"},
+			"FILE":               map[string]interface{}{"isa": "PBXFileReference", "path": "Sources/main.swift"},
+			"MAIN_GROUP":         map[string]interface{}{"isa": "PBXGroup"},
+		},
+	}
+
+	w, err := newPBXProjWriter(rawProj, "Project")
+	require.NoError(t, err)
+
+	for id, want := range map[string]string{
+		"PROJECT":            "Project object",
+		"PROJECT_CONFIGS":    `Build configuration list for PBXProject "Project"`,
+		"TARGET_CONFIGS":     `Build configuration list for PBXNativeTarget "App"`,
+		"SCRIPT_PHASE":       "ShellScript",
+		"REMOTE_PACKAGE":     `XCRemoteSwiftPackageReference "Alamofire"`,
+		"LOCAL_PACKAGE":      `XCLocalSwiftPackageReference "../Packages/Core"`,
+		"PRODUCT_BUILD_FILE": "Alamofire in ShellScript",
+		"ORPHAN_BUILD_FILE":  "Sources/main.swift",
+		"MAIN_GROUP":         "",
+	} {
+		require.Equal(t, want, w.comment(id), id)
+	}
+}
@@ -12,6 +12,22 @@ type Proj struct {
 	BuildConfigurationList ConfigurationList
 	Targets                []Target
 	Attributes             ProjectAtributes
+	packageReferences      []SwiftPackageReference
+}
+
+// SwiftPackageReferences returns the Swift packages of the project.
+func (p Proj) SwiftPackageReferences() []SwiftPackageReference {
+	return p.packageReferences
+}
+
+// SwiftPackageReference returns the Swift package with the given ID.
+func (p Proj) SwiftPackageReference(id string) (SwiftPackageReference, bool) {
+	for _, reference := range p.packageReferences {
+		if reference.ID == id {
+			return reference, true
+		}
+	}
+	return SwiftPackageReference{}, false
 }
 
 func parseProj(id string, objects serialized.Object) (Proj, error) {
@@ -59,11 +75,31 @@ func parseProj(id string, objects serialized.Object) (Proj, error) {
 		targets = append(targets, target)
 	}
 
+	packageReferenceIDs, err := rawPBXProj.StringSlice("packageReferences")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return Proj{}, fmt.Errorf("failed to access package references: %s", err)
+	}
+
+	var packageReferences []SwiftPackageReference
+	for _, packageReferenceID := range packageReferenceIDs {
+		// dangling package reference, reported by Validate
+		if _, err := objects.Object(packageReferenceID); serialized.IsKeyNotFoundError(err) {
+			continue
+		}
+
+		packageReference, err := parseSwiftPackageReference(packageReferenceID, objects)
+		if err != nil {
+			return Proj{}, fmt.Errorf("failed to parse package reference with id: %s: %s", packageReferenceID, err)
+		}
+		packageReferences = append(packageReferences, packageReference)
+	}
+
 	return Proj{
 		ID:                     id,
 		BuildConfigurationList: buildConfigurationList,
 		Targets:                targets,
 		Attributes:             projectAttributes,
+		packageReferences:      packageReferences,
 	}, nil
 }
 
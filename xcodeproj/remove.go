@@ -0,0 +1,269 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// dependentReferenceKeys lists the properties by object isa, which make the object useless once the referenced object is removed,
+// like a PBXBuildFile of a removed file reference or a PBXTargetDependency on a removed target.
+var dependentReferenceKeys = map[string][]string{
+	"PBXBuildFile":          {"fileRef", "productRef"},
+	"PBXTargetDependency":   {"target", "targetProxy"},
+	"PBXContainerItemProxy": {"containerPortal", "remoteGlobalIDString"},
+	"PBXReferenceProxy":     {"remoteRef"},
+}
+
+// RemoveFile removes the file tree element (a file reference, a variant group or a version group) from the project,
+// together with its children and the build files referencing them in the targets' build phases.
+func (p *XcodeProj) RemoveFile(fileID string) error {
+	element, found, err := p.FileElementByID(fileID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("failed to find file with id: %s", fileID)
+	}
+	if _, ok := element.(*Group); ok {
+		return fmt.Errorf("element (%s) is a %s element, use RemoveGroup", fileID, GroupElementType)
+	}
+
+	return p.removeObjects(fileElementIDs(element))
+}
+
+// RemoveGroup removes the group from the project, together with every element below it
+// and the build files referencing these elements in the targets' build phases.
+func (p *XcodeProj) RemoveGroup(groupID string) error {
+	element, found, err := p.FileElementByID(groupID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("failed to find group with id: %s", groupID)
+	}
+	group, ok := element.(*Group)
+	if !ok {
+		return fmt.Errorf("element (%s) is not a %s element", groupID, GroupElementType)
+	}
+	if group.Parent() == nil {
+		return fmt.Errorf("the main group can not be removed")
+	}
+
+	return p.removeObjects(fileElementIDs(group))
+}
+
+// RemoveTarget removes the target from the project, together with its build phases, build configuration list,
+// product reference and the other targets' dependencies on it.
+// If removeSharedSchemes is true, the project's shared schemes referencing the target are removed on Save.
+func (p *XcodeProj) RemoveTarget(targetName string, removeSharedSchemes bool) error {
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return fmt.Errorf("failed to find target with name: %s", targetName)
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+	rawTarget, err := objects.Object(target.ID)
+	if err != nil {
+		return err
+	}
+
+	ids, err := ownedTargetObjectIDs(target, rawTarget, objects)
+	if err != nil {
+		return err
+	}
+
+	// the schemes are read before modifying the project, so a broken scheme leaves the project untouched
+	var schemePths []string
+	if removeSharedSchemes {
+		if schemePths, err = p.sharedSchemesOf(target.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := p.removeObjects(ids); err != nil {
+		return err
+	}
+
+	for _, pth := range schemePths {
+		p.removeScheme(pth)
+	}
+	return nil
+}
+
+func fileElementIDs(element FileElement) []string {
+	ids := []string{element.Base().ID}
+	for _, child := range ChildElements(element) {
+		ids = append(ids, fileElementIDs(child)...)
+	}
+	return ids
+}
+
+// optionalReferenceKeys are the object properties holding a single object ID, which are removed together with the referenced object.
+// The other single object ID properties (see referenceKeys) are required: their objects can not be removed.
+var optionalReferenceKeys = map[string]bool{
+	"baseConfigurationReference": true,
+	"productReference":           true,
+	"TestTargetID":               true,
+}
+
+// removeObjects deletes the objects and the objects depending on them (see dependentReferenceKeys),
+// then removes the references to the deleted objects from the remaining ones.
+// It fails without modifying the project if a remaining object requires a deleted one, like the PBXProject's mainGroup.
+func (p *XcodeProj) removeObjects(ids []string) error {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+
+	removed := map[string]bool{}
+	for _, id := range ids {
+		removed[id] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for id := range objects {
+			if removed[id] {
+				continue
+			}
+
+			object, err := objects.Object(id)
+			if err != nil {
+				return err
+			}
+			if isDependentObject(object, removed) {
+				removed[id] = true
+				changed = true
+			}
+		}
+	}
+
+	for id := range objects {
+		if removed[id] {
+			continue
+		}
+
+		object, err := objects.Object(id)
+		if err != nil {
+			return err
+		}
+		for _, key := range referenceKeys {
+			if referencedID, err := object.String(key); err == nil && removed[referencedID] && !optionalReferenceKeys[key] {
+				return fmt.Errorf("object (%s) requires the removed object (%s) as %s", id, referencedID, key)
+			}
+		}
+	}
+
+	for id := range removed {
+		delete(objects, id)
+	}
+	for id := range objects {
+		removeReferences(objects[id], removed)
+	}
+
+	return p.reloadProj()
+}
+
+func isDependentObject(object serialized.Object, removed map[string]bool) bool {
+	isa, err := object.String("isa")
+	if err != nil {
+		return false
+	}
+
+	for _, key := range dependentReferenceKeys[isa] {
+		if id, err := object.String(key); err == nil && removed[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// removeReferences removes the references to the removed IDs from the dictionary and the dictionaries below it:
+// the dictionary keys (like the TargetAttributes keys), the ID list elements (see referenceListKeys),
+// the optional ID properties (see optionalReferenceKeys) and the projectReferences entries of the PBXProject.
+func removeReferences(value interface{}, removed map[string]bool) {
+	var dict map[string]interface{}
+	switch v := value.(type) {
+	case serialized.Object:
+		dict = v
+	case map[string]interface{}:
+		dict = v
+	default:
+		return
+	}
+
+	for key, element := range dict {
+		if removed[key] {
+			delete(dict, key)
+			continue
+		}
+		if id, ok := element.(string); ok && removed[id] && optionalReferenceKeys[key] {
+			delete(dict, key)
+			continue
+		}
+
+		list, ok := element.([]interface{})
+		if !ok {
+			removeReferences(element, removed)
+			continue
+		}
+		if key != "projectReferences" && !isReferenceListKey(key) {
+			continue
+		}
+
+		remaining := []interface{}{}
+		for _, listElement := range list {
+			if !referencesRemoved(listElement, removed) {
+				remaining = append(remaining, listElement)
+			}
+		}
+		if len(remaining) != len(list) {
+			dict[key] = remaining
+		}
+	}
+}
+
+func referencesRemoved(value interface{}, removed map[string]bool) bool {
+	switch v := value.(type) {
+	case string:
+		return removed[v]
+	case map[string]interface{}:
+		for _, key := range []string{"ProductGroup", "ProjectRef"} {
+			if id, ok := v[key].(string); ok && removed[id] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sharedSchemesOf returns the paths of the project's shared schemes, which reference the target.
+func (p *XcodeProj) sharedSchemesOf(targetID string) ([]string, error) {
+	pths, err := filepath.Glob(filepath.Join(p.Path, "xcshareddata", "xcschemes", "*.xcscheme"))
+	if err != nil {
+		return nil, err
+	}
+
+	container := "container:" + filepath.Base(p.Path)
+	var schemePths []string
+	for _, pth := range pths {
+		scheme, err := xcscheme.Open(pth)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reference := range scheme.BuildableReferences() {
+			if reference.BlueprintIdentifier == targetID && reference.ReferencedContainer == container {
+				schemePths = append(schemePths, pth)
+				break
+			}
+		}
+	}
+	return schemePths, nil
+}
@@ -0,0 +1,195 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_RemoveFile(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	require.NoError(t, proj.RemoveFile("7D5B35FF20E28EE80022BAE6"))
+
+	references, err := proj.ReferencesTo("7D5B35FF20E28EE80022BAE6")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(references))
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	require.NotContains(t, objects, "7D5B35FF20E28EE80022BAE6")
+	require.NotContains(t, objects, "7D5B360020E28EE80022BAE6")
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, SourcesBuildPhaseType, target.BuildPhases()[0].Type)
+	require.Equal(t, 1, len(target.BuildPhases()[0].Files))
+
+	err = proj.RemoveFile("7D5B35FE20E28EE80022BAE6")
+	require.EqualError(t, err, "element (7D5B35FE20E28EE80022BAE6) is a PBXGroup element, use RemoveGroup")
+
+	t.Log("variant group")
+	{
+		require.NoError(t, proj.RemoveFile("7D5B360320E28EE80022BAE6"))
+
+		for _, id := range []string{"7D5B360320E28EE80022BAE6", "7D5B360420E28EE80022BAE6"} {
+			references, err := proj.ReferencesTo(id)
+			require.NoError(t, err)
+			require.Equal(t, 0, len(references))
+			require.NotContains(t, objects, id)
+		}
+	}
+}
+
+func TestXcodeProj_RemoveGroup(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	buildFiles, err := proj.ObjectsByISA("PBXBuildFile")
+	require.NoError(t, err)
+	require.Equal(t, 11, len(buildFiles))
+
+	require.NoError(t, proj.RemoveGroup("7D5B35FE20E28EE80022BAE6"))
+
+	mainGroup, err := proj.MainGroup()
+	require.NoError(t, err)
+	var displayNames []string
+	for _, child := range mainGroup.Children {
+		displayNames = append(displayNames, child.Base().DisplayName())
+	}
+	require.Equal(t, []string{"XcodeProjUITests", "TodayExtension", "Frameworks", "Products"}, displayNames)
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type == SourcesBuildPhaseType || buildPhase.Type == ResourcesBuildPhaseType {
+			require.Equal(t, 0, len(buildPhase.Files))
+		}
+	}
+
+	buildFiles, err = proj.ObjectsByISA("PBXBuildFile")
+	require.NoError(t, err)
+	require.Equal(t, 6, len(buildFiles))
+
+	err = proj.RemoveGroup("7D5B35F320E28EE80022BAE6")
+	require.EqualError(t, err, "the main group can not be removed")
+
+	t.Log("required reference")
+	{
+		objects, err := proj.RawProj.Object("objects")
+		require.NoError(t, err)
+		count := len(objects)
+
+		err = proj.RemoveGroup("7D5B35FD20E28EE80022BAE6")
+		require.EqualError(t, err, "object (7D5B35F420E28EE80022BAE6) requires the removed object (7D5B35FD20E28EE80022BAE6) as productRefGroup")
+		require.Equal(t, count, len(objects))
+		require.Contains(t, objects, "7D5B35FC20E28EE80022BAE6")
+	}
+}
+
+func TestXcodeProj_RemoveTarget(t *testing.T) {
+	const todayExtensionID = "7D03430C20F4BB070050B6A6"
+
+	dir := t.TempDir()
+	projectPth := filepath.Join(dir, "XcodeProj.xcodeproj")
+	schemesDir := filepath.Join(projectPth, "xcshareddata", "xcschemes")
+	require.NoError(t, os.MkdirAll(schemesDir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(testhelper.XcodeProjectTest), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(schemesDir, "TodayExtension.xcscheme"), []byte(removeTargetTestScheme(todayExtensionID, "container:XcodeProj.xcodeproj")), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(schemesDir, "XcodeProj.xcscheme"), []byte(removeTargetTestScheme("7D5B35FB20E28EE80022BAE6", "container:XcodeProj.xcodeproj")), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(schemesDir, "Other.xcscheme"), []byte(removeTargetTestScheme(todayExtensionID, "container:Other.xcodeproj")), 0644))
+
+	proj, err := Open(projectPth)
+	require.NoError(t, err)
+
+	require.NoError(t, proj.RemoveTarget("TodayExtension", true))
+
+	schemes, err := filepath.Glob(filepath.Join(schemesDir, "*.xcscheme"))
+	require.NoError(t, err)
+	require.Equal(t, 3, len(schemes))
+
+	_, ok := proj.Proj.TargetByName("TodayExtension")
+	require.False(t, ok)
+
+	for _, id := range []string{
+		todayExtensionID,
+		"7D03430D20F4BB070050B6A6", // product reference
+		"7D03431A20F4BB070050B6A6", // embedded product build file
+		"7D03431920F4BB070050B6A6", // dependency of XcodeProj
+		"7D03431820F4BB070050B6A6", // container item proxy
+	} {
+		references, err := proj.ReferencesTo(id)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(references), id)
+
+		objects, err := proj.RawProj.Object("objects")
+		require.NoError(t, err)
+		require.NotContains(t, objects, id)
+	}
+
+	configurationLists, err := proj.ObjectsByISA("XCConfigurationList")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(configurationLists))
+	configurations, err := proj.ObjectsByISA("XCBuildConfiguration")
+	require.NoError(t, err)
+	require.Equal(t, 6, len(configurations))
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, 0, len(target.Dependencies))
+
+	attributes, err := proj.Proj.Attributes.TargetAttributes.Object(todayExtensionID)
+	require.Error(t, err)
+	require.Nil(t, attributes)
+
+	require.NoError(t, proj.Save())
+	reopened, err := Open(projectPth)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(reopened.Proj.Targets))
+
+	schemes, err = filepath.Glob(filepath.Join(schemesDir, "*.xcscheme"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(schemesDir, "Other.xcscheme"), filepath.Join(schemesDir, "XcodeProj.xcscheme")}, schemes)
+
+	t.Log("invalid shared scheme leaves the project untouched")
+	{
+		require.NoError(t, ioutil.WriteFile(filepath.Join(schemesDir, "Invalid.xcscheme"), []byte("<Scheme"), 0644))
+
+		proj, err := Open(projectPth)
+		require.NoError(t, err)
+		original := string(proj.originalContents)
+
+		require.Error(t, proj.RemoveTarget("XcodeProj", true))
+		_, ok := proj.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+
+		content, err := proj.perObjectModify()
+		require.NoError(t, err)
+		require.Equal(t, original, string(content))
+	}
+}
+
+func removeTargetTestScheme(blueprintIdentifier, referencedContainer string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme LastUpgradeVersion = "1000" version = "1.3">
+   <BuildAction parallelizeBuildables = "YES" buildImplicitDependencies = "YES">
+      <BuildActionEntries>
+         <BuildActionEntry buildForTesting = "YES" buildForRunning = "YES" buildForProfiling = "YES" buildForArchiving = "YES" buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "` + blueprintIdentifier + `"
+               BuildableName = "Target.app"
+               BlueprintName = "Target"
+               ReferencedContainer = "` + referencedContainer + `">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+</Scheme>
+`
+}
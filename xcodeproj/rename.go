@@ -0,0 +1,195 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// RenameTarget renames the target and updates the dependent references like Xcode does:
+// the target's productName, the PRODUCT_NAME and TEST_TARGET_NAME build settings set to the old name,
+// the product reference's path, the remoteInfo of the container item proxies pointing at the target
+// and the BlueprintName and BuildableName of the target's BuildableReferences in the schemes.
+// The shared and user schemes of the project and of the workspaces next to the project are updated on Save.
+func (p *XcodeProj) RenameTarget(oldName, newName string) error {
+	target, ok := p.Proj.TargetByName(oldName)
+	if !ok {
+		return fmt.Errorf("failed to find target with name: %s", oldName)
+	}
+	if newName == "" {
+		return fmt.Errorf("empty target name")
+	}
+	if _, ok := p.Proj.TargetByName(newName); ok {
+		return fmt.Errorf("target already exists with name: %s", newName)
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+	rawTarget, err := objects.Object(target.ID)
+	if err != nil {
+		return err
+	}
+
+	var productReference serialized.Object
+	oldProductName, newProductName := "", ""
+	if productReferenceID, err := rawTarget.String("productReference"); err == nil {
+		if productReference, err = objects.Object(productReferenceID); err != nil {
+			return err
+		}
+		if pth, err := productReference.String("path"); err == nil && strings.TrimSuffix(pth, path.Ext(pth)) == oldName {
+			oldProductName, newProductName = pth, newName+path.Ext(pth)
+		}
+	}
+
+	// the schemes are read before modifying the project, so a broken scheme leaves the project untouched
+	schemes, err := p.renamedTargetSchemes(target.ID, oldName, newName, oldProductName, newProductName)
+	if err != nil {
+		return err
+	}
+
+	rawTarget["name"] = newName
+	if productName, err := rawTarget.String("productName"); err == nil && productName == oldName {
+		rawTarget["productName"] = newName
+	}
+	if newProductName != "" {
+		productReference["path"] = newProductName
+	}
+
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		renameBuildSetting(buildConfiguration.BuildSettings, "PRODUCT_NAME", oldName, newName)
+	}
+	for _, other := range p.Proj.Targets {
+		for _, buildConfiguration := range other.BuildConfigurationList.BuildConfigurations {
+			renameBuildSetting(buildConfiguration.BuildSettings, "TEST_TARGET_NAME", oldName, newName)
+		}
+	}
+
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil {
+			return err
+		}
+		if isa, err := object.String("isa"); err != nil || isa != "PBXContainerItemProxy" {
+			continue
+		}
+		if remoteGlobalID, err := object.String("remoteGlobalIDString"); err == nil && remoteGlobalID == target.ID {
+			object["remoteInfo"] = newName
+		}
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return err
+	}
+
+	for _, scheme := range schemes {
+		p.saveScheme(scheme)
+	}
+	return nil
+}
+
+func renameBuildSetting(buildSettings serialized.Object, key, oldValue, newValue string) {
+	if value, err := buildSettings.String(key); err == nil && value == oldValue {
+		buildSettings[key] = newValue
+	}
+}
+
+// renamedTargetSchemes returns the schemes of the project and of the workspaces next to the project,
+// which reference the target, with the target's BuildableReferences updated.
+func (p *XcodeProj) renamedTargetSchemes(targetID, oldName, newName, oldProductName, newProductName string) ([]xcscheme.Scheme, error) {
+	containers := []string{p.Path}
+	workspaces, err := filepath.Glob(filepath.Join(filepath.Dir(p.Path), "*.xcworkspace"))
+	if err != nil {
+		return nil, err
+	}
+	containers = append(containers, workspaces...)
+
+	var renamed []xcscheme.Scheme
+	for _, container := range containers {
+		schemes, err := xcscheme.FindSchemesIn(container)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, scheme := range schemes {
+			changed := false
+			for _, reference := range schemeBuildableReferences(&scheme) {
+				if reference.BlueprintIdentifier != targetID {
+					continue
+				}
+				if pth, err := reference.ReferencedContainerAbsPath(filepath.Dir(container)); err != nil || pth != p.Path {
+					continue
+				}
+
+				if reference.BlueprintName == oldName {
+					reference.BlueprintName = newName
+					changed = true
+				}
+				if oldProductName != "" && reference.BuildableName == oldProductName {
+					reference.BuildableName = newProductName
+					changed = true
+				}
+			}
+
+			if changed {
+				renamed = append(renamed, scheme)
+			}
+		}
+	}
+	return renamed, nil
+}
+
+// schemeBuildableReferences returns every BuildableReference of the scheme's actions, to modify them in place.
+func schemeBuildableReferences(scheme *xcscheme.Scheme) []*xcscheme.BuildableReference {
+	var references []*xcscheme.BuildableReference
+	addExecutionActions := func(actions []xcscheme.ExecutionAction) {
+		for i := range actions {
+			if actions[i].ActionContent.EnvironmentBuildable != nil {
+				references = append(references, actions[i].ActionContent.EnvironmentBuildable)
+			}
+		}
+	}
+	addRunnables := func(buildableProductRunnable *xcscheme.BuildableProductRunnable, remoteRunnable *xcscheme.RemoteRunnable, macroExpansion *xcscheme.MacroExpansion) {
+		if buildableProductRunnable != nil {
+			references = append(references, &buildableProductRunnable.BuildableReference)
+		}
+		if remoteRunnable != nil {
+			references = append(references, &remoteRunnable.BuildableReference)
+		}
+		if macroExpansion != nil {
+			references = append(references, &macroExpansion.BuildableReference)
+		}
+	}
+
+	for i := range scheme.BuildAction.BuildActionEntries {
+		references = append(references, &scheme.BuildAction.BuildActionEntries[i].BuildableReference)
+	}
+	for i := range scheme.TestAction.Testables {
+		references = append(references, &scheme.TestAction.Testables[i].BuildableReference)
+	}
+	for i := range scheme.TestAction.CodeCoverageTargets {
+		references = append(references, &scheme.TestAction.CodeCoverageTargets[i])
+	}
+	if scheme.TestAction.MacroExpansion != nil {
+		references = append(references, &scheme.TestAction.MacroExpansion.BuildableReference)
+	}
+	addRunnables(scheme.LaunchAction.BuildableProductRunnable, scheme.LaunchAction.RemoteRunnable, scheme.LaunchAction.MacroExpansion)
+	addRunnables(scheme.ProfileAction.BuildableProductRunnable, scheme.ProfileAction.RemoteRunnable, scheme.ProfileAction.MacroExpansion)
+
+	for _, actions := range [][]xcscheme.ExecutionAction{
+		scheme.BuildAction.PreActions, scheme.BuildAction.PostActions,
+		scheme.TestAction.PreActions, scheme.TestAction.PostActions,
+		scheme.LaunchAction.PreActions, scheme.LaunchAction.PostActions,
+		scheme.ProfileAction.PreActions, scheme.ProfileAction.PostActions,
+		scheme.AnalyzeAction.PreActions, scheme.AnalyzeAction.PostActions,
+		scheme.ArchiveAction.PreActions, scheme.ArchiveAction.PostActions,
+	} {
+		addExecutionActions(actions)
+	}
+	return references
+}
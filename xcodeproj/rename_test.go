@@ -0,0 +1,150 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_RenameTarget(t *testing.T) {
+	dir := t.TempDir()
+	projectPth := filepath.Join(dir, "XcodeProj.xcodeproj")
+	require.NoError(t, os.MkdirAll(projectPth, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(testhelper.XcodeProjectTest), 0644))
+
+	schemes := map[string]string{
+		filepath.Join(projectPth, "xcshareddata", "xcschemes", "TodayExtension.xcscheme"):                   renameTargetTestScheme("container:XcodeProj.xcodeproj"),
+		filepath.Join(projectPth, "xcuserdata", "john.xcuserdatad", "xcschemes", "TodayExtension.xcscheme"): renameTargetTestScheme("container:XcodeProj.xcodeproj"),
+		filepath.Join(dir, "XcodeProj.xcworkspace", "xcshareddata", "xcschemes", "Workspace.xcscheme"):      renameTargetTestScheme("container:XcodeProj.xcodeproj"),
+		filepath.Join(dir, "XcodeProj.xcworkspace", "xcshareddata", "xcschemes", "Other.xcscheme"):          renameTargetTestScheme("container:Other/XcodeProj.xcodeproj"),
+	}
+	for pth, content := range schemes {
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	proj, err := Open(projectPth)
+	require.NoError(t, err)
+
+	err = proj.RenameTarget("TodayExtension", "XcodeProj")
+	require.EqualError(t, err, "target already exists with name: XcodeProj")
+
+	require.NoError(t, proj.RenameTarget("TodayExtension", "Widget"))
+
+	_, ok := proj.Proj.TargetByName("TodayExtension")
+	require.False(t, ok)
+	target, ok := proj.Proj.TargetByName("Widget")
+	require.True(t, ok)
+	require.Equal(t, "7D03430C20F4BB070050B6A6", target.ID)
+	require.Equal(t, "Widget.appex", target.ProductReference.Path)
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	proxy, err := objects.Object("7D03431820F4BB070050B6A6")
+	require.NoError(t, err)
+	require.Equal(t, "Widget", proxy["remoteInfo"])
+
+	for pth, original := range schemes {
+		content, err := ioutil.ReadFile(pth)
+		require.NoError(t, err)
+		require.Equal(t, original, string(content), pth)
+	}
+
+	require.NoError(t, proj.Save())
+	content, err := ioutil.ReadFile(filepath.Join(projectPth, "project.pbxproj"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "7D03431A20F4BB070050B6A6 /* Widget.appex in Embed App Extensions */ = {isa = PBXBuildFile; fileRef = 7D03430D20F4BB070050B6A6 /* Widget.appex */;")
+	require.NotContains(t, string(content), "TodayExtension.appex")
+
+	for pth, original := range schemes {
+		content, err := ioutil.ReadFile(pth)
+		require.NoError(t, err)
+
+		if strings.HasSuffix(pth, "Other.xcscheme") {
+			require.Equal(t, original, string(content))
+			continue
+		}
+
+		want := strings.Replace(original, `BlueprintName = "TodayExtension"`, `BlueprintName = "Widget"`, -1)
+		want = strings.Replace(want, `BuildableName = "TodayExtension.appex"`, `BuildableName = "Widget.appex"`, -1)
+		require.Equal(t, want, string(content), pth)
+	}
+
+	t.Log("invalid scheme leaves the project untouched")
+	{
+		require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "xcshareddata", "xcschemes", "Invalid.xcscheme"), []byte("<Scheme"), 0644))
+
+		proj, err := Open(projectPth)
+		require.NoError(t, err)
+		original := string(proj.originalContents)
+
+		require.Error(t, proj.RenameTarget("Widget", "Extension"))
+		_, ok := proj.Proj.TargetByName("Widget")
+		require.True(t, ok)
+
+		content, err := proj.perObjectModify()
+		require.NoError(t, err)
+		require.Equal(t, original, string(content))
+	}
+}
+
+func renameTargetTestScheme(referencedContainer string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1000"
+   version = "1.3">
+   <BuildAction
+      parallelizeBuildables = "YES"
+      buildImplicitDependencies = "YES">
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "YES"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D03430C20F4BB070050B6A6"
+               BuildableName = "TodayExtension.appex"
+               BlueprintName = "TodayExtension"
+               ReferencedContainer = "` + referencedContainer + `">
+            </BuildableReference>
+         </BuildActionEntry>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "YES"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+               BuildableName = "XcodeProj.app"
+               BlueprintName = "XcodeProj"
+               ReferencedContainer = "` + referencedContainer + `">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <LaunchAction
+      buildConfiguration = "Debug">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "7D03430C20F4BB070050B6A6"
+            BuildableName = "TodayExtension.appex"
+            BlueprintName = "TodayExtension"
+            ReferencedContainer = "` + referencedContainer + `">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </LaunchAction>
+</Scheme>
+`
+}
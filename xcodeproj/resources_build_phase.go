@@ -45,40 +45,16 @@ func parseResourcesBuildPhase(id string, objects serialized.Object) (resourcesBu
 	}, nil
 }
 
-// buildFile represents a PBXBuildFile element
-// 47C11A4A21FF63970084FD7F /* Assets.xcassets in Resources */ = {isa = PBXBuildFile; fileRef = 47C11A4921FF63970084FD7F /* Assets.xcassets */; };
-type buildFile struct {
-	fileRef string
-}
-
-func parseBuildFile(id string, objects serialized.Object) (buildFile, error) {
-	rawBuildFile, err := objects.Object(id)
-	if err != nil {
-		return buildFile{}, err
-	}
-	if isa, err := rawBuildFile.String("isa"); err != nil {
-		return buildFile{}, err
-	} else if isa != "PBXBuildFile" {
-		return buildFile{}, fmt.Errorf("not a PBXBuildFile element")
-	}
-
-	fileRef, err := rawBuildFile.String("fileRef")
-	if err != nil {
-		return buildFile{}, err
-	}
-
-	return buildFile{
-		fileRef: fileRef,
-	}, nil
-}
-
 type sourceTree int
 
 const (
-	unsupportedParent sourceTree = iota
+	// buildSettingParent is a source tree named after a build setting, like: BUILT_PRODUCTS_DIR, SDKROOT or DEVELOPER_DIR
+	buildSettingParent sourceTree = iota
 	groupParent
 	absoluteParentPath
 	undefinedParent
+	sourceRootParent
+	projectRootParent
 )
 
 // PBXFileReference
@@ -143,13 +119,9 @@ func resolveObjectAbsolutePath(targetID string, projectID string, projectPath st
 		return "", err
 	}
 
-	projectDirPath, err := project.String("projectDirPath")
+	projectRootEntry, err := projectRootEntry(projectID, projectPath, objects)
 	if err != nil {
-		return "", fmt.Errorf("key projectDirPath not found, project: %s, error: %s", project, err)
-	}
-	projectRoot, err := project.String("projectRoot")
-	if err != nil {
-		return "", fmt.Errorf("key projectRoot not found, project: %s, error: %s", project, err)
+		return "", err
 	}
 	mainGroup, err := project.String("mainGroup")
 	if err != nil {
@@ -160,10 +132,7 @@ func resolveObjectAbsolutePath(targetID string, projectID string, projectPath st
 	if err != nil {
 		return "", fmt.Errorf("failed to find target ID in project, error: %s", err)
 	}
-	pathInProjectTree = append(pathInProjectTree, projectEntry{
-		path:         path.Join(projectPath, "..", projectDirPath, projectRoot),
-		pathRelation: absoluteParentPath,
-	})
+	pathInProjectTree = append(pathInProjectTree, projectRootEntry)
 
 	path, err := resolveFilePath(pathInProjectTree)
 	if err != nil {
@@ -172,10 +141,57 @@ func resolveObjectAbsolutePath(targetID string, projectID string, projectPath st
 	return path, nil
 }
 
+// projectRootEntry returns the entry of the directory the main group is relative to.
+func projectRootEntry(projectID string, projectPath string, objects serialized.Object) (projectEntry, error) {
+	project, err := objects.Object(projectID)
+	if err != nil {
+		return projectEntry{}, err
+	}
+
+	projectDirPath, err := project.String("projectDirPath")
+	if err != nil {
+		return projectEntry{}, fmt.Errorf("key projectDirPath not found, project: %s, error: %s", project, err)
+	}
+	projectRoot, err := project.String("projectRoot")
+	if err != nil {
+		return projectEntry{}, fmt.Errorf("key projectRoot not found, project: %s, error: %s", project, err)
+	}
+
+	return projectEntry{
+		path:         path.Join(projectPath, "..", projectDirPath, projectRoot),
+		pathRelation: projectRootParent,
+	}, nil
+}
+
 type projectEntry struct {
 	id           string
 	pathRelation sourceTree
 	path         string
+	// buildSetting is the source tree build setting of the buildSettingParent entries.
+	buildSetting string
+}
+
+func newProjectEntry(id, entryPath, sourceTreeRaw string) projectEntry {
+	entry := projectEntry{
+		id:   id,
+		path: entryPath,
+	}
+
+	switch sourceTreeRaw {
+	case "<group>":
+		entry.pathRelation = groupParent
+	case "<absolute>":
+		entry.pathRelation = absoluteParentPath
+	case "":
+		entry.pathRelation = undefinedParent
+	case "SOURCE_ROOT":
+		entry.pathRelation = sourceRootParent
+	default:
+		entry.pathRelation = buildSettingParent
+		entry.buildSetting = sourceTreeRaw
+	}
+
+	return entry
 }
 
 func findInProjectTree(target string, currentID string, object serialized.Object, visited *[]string) ([]projectEntry, error) {
@@ -196,23 +212,7 @@ func findInProjectTree(target string, currentID string, object serialized.Object
 	if err != nil {
 		return nil, err
 	}
-	var pathRelation sourceTree
-	switch sourceTreeRaw {
-	case "<group>":
-		pathRelation = groupParent
-	case "<absolute>":
-		pathRelation = absoluteParentPath
-	case "":
-		pathRelation = undefinedParent
-	default:
-		pathRelation = unsupportedParent
-	}
-
-	treeNode := projectEntry{
-		id:           currentID,
-		path:         entryPath,
-		pathRelation: pathRelation,
-	}
+	treeNode := newProjectEntry(currentID, entryPath, sourceTreeRaw)
 
 	if currentID == target {
 		return []projectEntry{treeNode}, nil
@@ -234,17 +234,76 @@ func findInProjectTree(target string, currentID string, object serialized.Object
 }
 
 func resolveFilePath(nodes []projectEntry) (string, error) {
+	resolved, err := resolveFileElementPath(nodes, nil)
+	if err != nil {
+		return "", err
+	}
+	return resolved.Path, nil
+}
+
+// resolveFileElementPath resolves the path of the first node, the nodes list the file tree elements from the element up to the project root.
+// The source tree build settings are looked up in buildSettings,
+// the missing ones are kept as build setting references (like: $(BUILT_PRODUCTS_DIR)/App.app) and the path is reported as symbolic.
+func resolveFileElementPath(nodes []projectEntry, buildSettings serialized.Object) (ResolvedPath, error) {
 	var partialPath string
 	for _, entry := range nodes {
 		switch entry.pathRelation {
 		case groupParent:
 			partialPath = path.Join(entry.path, partialPath)
-		case absoluteParentPath:
-			return path.Join(entry.path, partialPath), nil
+		case absoluteParentPath, projectRootParent:
+			return ResolvedPath{Path: path.Join(entry.path, partialPath)}, nil
 		case undefinedParent:
-		case unsupportedParent:
-			return "", fmt.Errorf("failed to resolve path, unsupported path relation")
+		case sourceRootParent:
+			sourceRoot, err := sourceRootPath(nodes, buildSettings)
+			if err != nil {
+				return ResolvedPath{}, err
+			}
+			return ResolvedPath{Path: path.Join(sourceRoot, entry.path, partialPath), Symbolic: sourceRoot == "$(SOURCE_ROOT)"}, nil
+		case buildSettingParent:
+			value, err := sourceTreeBuildSetting(entry.buildSetting, buildSettings)
+			if err != nil {
+				return ResolvedPath{}, err
+			}
+			if value == "" {
+				return ResolvedPath{Path: path.Join(fmt.Sprintf("$(%s)", entry.buildSetting), entry.path, partialPath), Symbolic: true}, nil
+			}
+			return ResolvedPath{Path: path.Join(value, entry.path, partialPath)}, nil
+		}
+	}
+	return ResolvedPath{Path: partialPath}, nil
+}
+
+// sourceRootPath returns the SOURCE_ROOT: the project root or the SOURCE_ROOT (SRCROOT) build setting.
+func sourceRootPath(nodes []projectEntry, buildSettings serialized.Object) (string, error) {
+	for _, entry := range nodes {
+		if entry.pathRelation == projectRootParent {
+			return entry.path, nil
+		}
+	}
+
+	for _, key := range []string{"SOURCE_ROOT", "SRCROOT"} {
+		value, err := sourceTreeBuildSetting(key, buildSettings)
+		if err != nil {
+			return "", err
 		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	return "$(SOURCE_ROOT)", nil
+}
+
+func sourceTreeBuildSetting(key string, buildSettings serialized.Object) (string, error) {
+	if buildSettings == nil {
+		return "", nil
+	}
+
+	value, err := buildSettings.String(key)
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return "", nil
+		}
+		return "", err
 	}
-	return partialPath, nil
+	return ExpandBuildSetting(value, buildSettings)
 }
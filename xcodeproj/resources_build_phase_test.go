@@ -263,6 +263,45 @@ func Test_resolveFilePath(t *testing.T) {
 			want:    path.Join("group", "Images.xcassets"),
 			wantErr: false,
 		},
+		{
+			name: "source root",
+			nodes: []projectEntry{
+				{
+					path:         "Images.xcassets",
+					pathRelation: groupParent,
+				},
+				{
+					path:         "Resources",
+					pathRelation: sourceRootParent,
+				},
+				{
+					path:         "group",
+					pathRelation: groupParent,
+				},
+				{
+					path:         "project_root",
+					pathRelation: projectRootParent,
+				},
+			},
+			want:    path.Join("project_root", "Resources", "Images.xcassets"),
+			wantErr: false,
+		},
+		{
+			name: "build setting source tree",
+			nodes: []projectEntry{
+				{
+					path:         "App.app",
+					pathRelation: buildSettingParent,
+					buildSetting: "BUILT_PRODUCTS_DIR",
+				},
+				{
+					path:         "project_root",
+					pathRelation: projectRootParent,
+				},
+			},
+			want:    "$(BUILT_PRODUCTS_DIR)/App.app",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,44 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// schemeChange is a scheme file operation of the project, which is applied on Save.
+type schemeChange struct {
+	// scheme is written to its Path, if remove is false.
+	scheme xcscheme.Scheme
+	// pth is removed, if remove is true.
+	pth    string
+	remove bool
+}
+
+// saveScheme queues writing the scheme to its Path.
+func (p *XcodeProj) saveScheme(scheme xcscheme.Scheme) {
+	p.schemeChanges = append(p.schemeChanges, schemeChange{scheme: scheme})
+}
+
+// removeScheme queues removing the scheme file.
+func (p *XcodeProj) removeScheme(pth string) {
+	p.schemeChanges = append(p.schemeChanges, schemeChange{pth: pth, remove: true})
+}
+
+// saveSchemeChanges applies the queued scheme file operations in order.
+func (p XcodeProj) saveSchemeChanges() error {
+	for _, change := range p.schemeChanges {
+		if change.remove {
+			if err := os.Remove(change.pth); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove scheme (%s): %s", change.pth, err)
+			}
+			continue
+		}
+
+		if err := change.scheme.Save(); err != nil {
+			return fmt.Errorf("failed to save scheme (%s): %s", change.scheme.Path, err)
+		}
+	}
+	return nil
+}
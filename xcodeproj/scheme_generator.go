@@ -0,0 +1,193 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+const (
+	defaultSchemeVersion = "1.3"
+	lldbDebugger         = "Xcode.DebuggerFoundation.Debugger.LLDB"
+	lldbLauncher         = "Xcode.DebuggerFoundation.Launcher.LLDB"
+	toolProductType      = "com.apple.product-type.tool"
+)
+
+// DefaultScheme returns the scheme Xcode autocreates for the target:
+// it builds the target, tests the test targets depending on the target and runs the target if it is an app or a command line tool.
+// The test, launch and analyze actions use the Debug configuration, the profile and archive actions the project's default configuration.
+// The scheme's Path points to the project's shared schemes directory.
+func (p XcodeProj) DefaultScheme(target Target) xcscheme.Scheme {
+	debugConfiguration, releaseConfiguration := p.defaultSchemeConfigurations()
+	reference := p.buildableReference(target)
+
+	buildActionEntry := xcscheme.BuildActionEntry{
+		BuildForTesting:    "YES",
+		BuildForRunning:    "YES",
+		BuildForProfiling:  "YES",
+		BuildForArchiving:  "YES",
+		BuildForAnalyzing:  "YES",
+		BuildableReference: reference,
+	}
+	if target.IsTestProduct() || target.IsUITestProduct() {
+		buildActionEntry.BuildForRunning = "NO"
+		buildActionEntry.BuildForProfiling = "NO"
+		buildActionEntry.BuildForArchiving = "NO"
+		buildActionEntry.BuildForAnalyzing = "NO"
+	}
+
+	var testables []xcscheme.TestableReference
+	for _, testTarget := range p.testTargetsOf(target) {
+		testables = append(testables, xcscheme.TestableReference{
+			Skipped:            "NO",
+			BuildableReference: p.buildableReference(testTarget),
+		})
+	}
+
+	scheme := xcscheme.Scheme{
+		LastUpgradeVersion: p.lastUpgradeCheck(),
+		Version:            defaultSchemeVersion,
+		BuildAction: xcscheme.BuildAction{
+			ParallelizeBuildables:     "YES",
+			BuildImplicitDependencies: "YES",
+			BuildActionEntries:        []xcscheme.BuildActionEntry{buildActionEntry},
+		},
+		TestAction: xcscheme.TestAction{
+			BuildConfiguration:           debugConfiguration,
+			SelectedDebuggerIdentifier:   lldbDebugger,
+			SelectedLauncherIdentifier:   lldbLauncher,
+			ShouldUseLaunchSchemeArgsEnv: "YES",
+			Testables:                    testables,
+		},
+		LaunchAction: xcscheme.LaunchAction{
+			BuildConfiguration:             debugConfiguration,
+			SelectedDebuggerIdentifier:     lldbDebugger,
+			SelectedLauncherIdentifier:     lldbLauncher,
+			LaunchStyle:                    "0",
+			UseCustomWorkingDirectory:      "NO",
+			IgnoresPersistentStateOnLaunch: "NO",
+			DebugDocumentVersioning:        "YES",
+			DebugServiceExtension:          "internal",
+			AllowLocationSimulation:        "YES",
+		},
+		ProfileAction: xcscheme.ProfileAction{
+			BuildConfiguration:           releaseConfiguration,
+			ShouldUseLaunchSchemeArgsEnv: "YES",
+			UseCustomWorkingDirectory:    "NO",
+			DebugDocumentVersioning:      "YES",
+		},
+		AnalyzeAction: xcscheme.AnalyzeAction{
+			BuildConfiguration: debugConfiguration,
+		},
+		ArchiveAction: xcscheme.ArchiveAction{
+			BuildConfiguration:       releaseConfiguration,
+			RevealArchiveInOrganizer: "YES",
+		},
+		Name: target.Name,
+		Path: filepath.Join(p.Path, "xcshareddata", "xcschemes", target.Name+".xcscheme"),
+	}
+
+	if target.IsAppProduct() || target.ProductType == toolProductType {
+		scheme.LaunchAction.BuildableProductRunnable = &xcscheme.BuildableProductRunnable{RunnableDebuggingMode: "0", BuildableReference: reference}
+		scheme.ProfileAction.BuildableProductRunnable = &xcscheme.BuildableProductRunnable{RunnableDebuggingMode: "0", BuildableReference: reference}
+	} else {
+		scheme.LaunchAction.MacroExpansion = &xcscheme.MacroExpansion{BuildableReference: reference}
+		scheme.ProfileAction.MacroExpansion = &xcscheme.MacroExpansion{BuildableReference: reference}
+	}
+
+	return scheme
+}
+
+// GenerateSharedScheme writes the default scheme (see DefaultScheme) of the target to the project's shared schemes directory.
+// It fails if the project already has a shared scheme with the target's name.
+func (p XcodeProj) GenerateSharedScheme(targetName string) (xcscheme.Scheme, error) {
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return xcscheme.Scheme{}, fmt.Errorf("target not found: %s", targetName)
+	}
+
+	scheme := p.DefaultScheme(target)
+	if exist, err := pathutil.IsPathExists(scheme.Path); err != nil {
+		return xcscheme.Scheme{}, err
+	} else if exist {
+		return xcscheme.Scheme{}, fmt.Errorf("scheme already exists: %s", scheme.Path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(scheme.Path), 0755); err != nil {
+		return xcscheme.Scheme{}, err
+	}
+	if err := scheme.Save(); err != nil {
+		return xcscheme.Scheme{}, err
+	}
+	return scheme, nil
+}
+
+func (p XcodeProj) buildableReference(target Target) xcscheme.BuildableReference {
+	buildableName := filepath.Base(target.ProductReference.Path)
+	if target.ProductReference.Path == "" {
+		buildableName = target.Name
+	}
+
+	return xcscheme.BuildableReference{
+		BuildableIdentifier: "primary",
+		BlueprintIdentifier: target.ID,
+		BuildableName:       buildableName,
+		BlueprintName:       target.Name,
+		ReferencedContainer: "container:" + p.Name + ".xcodeproj",
+	}
+}
+
+// testTargetsOf returns the test targets depending on the target, or the target itself if it is a test target.
+func (p XcodeProj) testTargetsOf(target Target) []Target {
+	if target.IsTestProduct() || target.IsUITestProduct() {
+		return []Target{target}
+	}
+
+	var testTargets []Target
+	for _, candidate := range p.Proj.Targets {
+		if !candidate.IsTestProduct() && !candidate.IsUITestProduct() {
+			continue
+		}
+		for _, dependency := range candidate.Dependencies {
+			if dependency.Target.ID == target.ID {
+				testTargets = append(testTargets, candidate)
+				break
+			}
+		}
+	}
+	return testTargets
+}
+
+// defaultSchemeConfigurations returns the project's Debug configuration (or the first non default one) and its default configuration.
+func (p XcodeProj) defaultSchemeConfigurations() (string, string) {
+	configurationList := p.Proj.BuildConfigurationList
+	releaseConfiguration := configurationList.DefaultConfigurationName
+	if releaseConfiguration == "" {
+		releaseConfiguration = "Release"
+	}
+
+	if _, ok := configurationList.BuildConfiguration("Debug"); ok {
+		return "Debug", releaseConfiguration
+	}
+	for _, buildConfiguration := range configurationList.BuildConfigurations {
+		if buildConfiguration.Name != releaseConfiguration {
+			return buildConfiguration.Name, releaseConfiguration
+		}
+	}
+	return releaseConfiguration, releaseConfiguration
+}
+
+func (p XcodeProj) lastUpgradeCheck() string {
+	attributes, err := p.Attributes()
+	if err != nil {
+		return ""
+	}
+	lastUpgradeCheck, err := attributes.String("LastUpgradeCheck")
+	if err != nil {
+		return ""
+	}
+	return lastUpgradeCheck
+}
@@ -0,0 +1,164 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_GenerateSharedScheme(t *testing.T) {
+	projectPth := filepath.Join(t.TempDir(), "XcodeProj.xcodeproj")
+	require.NoError(t, os.MkdirAll(projectPth, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(testhelper.XcodeProjectTest), 0644))
+	proj, err := Open(projectPth)
+	require.NoError(t, err)
+
+	t.Log("app target")
+	{
+		scheme, err := proj.GenerateSharedScheme("XcodeProj")
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(projectPth, "xcshareddata", "xcschemes", "XcodeProj.xcscheme"), scheme.Path)
+
+		content, err := ioutil.ReadFile(scheme.Path)
+		require.NoError(t, err)
+		require.Equal(t, appSchemeContent, string(content))
+
+		schemes, err := proj.Schemes()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(schemes))
+		require.Equal(t, "XcodeProj", schemes[0].Name)
+
+		_, err = proj.GenerateSharedScheme("XcodeProj")
+		require.Error(t, err)
+	}
+
+	t.Log("app extension target")
+	{
+		target, ok := proj.Proj.TargetByName("TodayExtension")
+		require.True(t, ok)
+
+		scheme := proj.DefaultScheme(target)
+		require.Nil(t, scheme.LaunchAction.BuildableProductRunnable)
+		require.Equal(t, &xcscheme.MacroExpansion{BuildableReference: xcscheme.BuildableReference{
+			BuildableIdentifier: "primary",
+			BlueprintIdentifier: "7D03430C20F4BB070050B6A6",
+			BuildableName:       "TodayExtension.appex",
+			BlueprintName:       "TodayExtension",
+			ReferencedContainer: "container:XcodeProj.xcodeproj",
+		}}, scheme.LaunchAction.MacroExpansion)
+		require.Equal(t, 0, len(scheme.TestAction.Testables))
+
+		content, err := scheme.Marshal()
+		require.NoError(t, err)
+		require.Contains(t, string(content), "\n      <Testables>\n      </Testables>\n")
+	}
+
+	t.Log("test target")
+	{
+		target, ok := proj.Proj.TargetByName("XcodeProjUITests")
+		require.True(t, ok)
+
+		scheme := proj.DefaultScheme(target)
+		require.Equal(t, "YES", scheme.BuildAction.BuildActionEntries[0].BuildForTesting)
+		require.Equal(t, "NO", scheme.BuildAction.BuildActionEntries[0].BuildForRunning)
+		require.Equal(t, 1, len(scheme.TestAction.Testables))
+		require.Equal(t, "7D0342F020F4BA280050B6A6", scheme.TestAction.Testables[0].BuildableReference.BlueprintIdentifier)
+	}
+
+	_, err = proj.GenerateSharedScheme("NotExisting")
+	require.Error(t, err)
+}
+
+const appSchemeContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "0940"
+   version = "1.3">
+   <BuildAction
+      parallelizeBuildables = "YES"
+      buildImplicitDependencies = "YES">
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "YES"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+               BuildableName = "XcodeProj.app"
+               BlueprintName = "XcodeProj"
+               ReferencedContainer = "container:XcodeProj.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <TestAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      shouldUseLaunchSchemeArgsEnv = "YES">
+      <Testables>
+         <TestableReference
+            skipped = "NO">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D0342F020F4BA280050B6A6"
+               BuildableName = "XcodeProjUITests.xctest"
+               BlueprintName = "XcodeProjUITests"
+               ReferencedContainer = "container:XcodeProj.xcodeproj">
+            </BuildableReference>
+         </TestableReference>
+      </Testables>
+   </TestAction>
+   <LaunchAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      launchStyle = "0"
+      useCustomWorkingDirectory = "NO"
+      ignoresPersistentStateOnLaunch = "NO"
+      debugDocumentVersioning = "YES"
+      debugServiceExtension = "internal"
+      allowLocationSimulation = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+            BuildableName = "XcodeProj.app"
+            BlueprintName = "XcodeProj"
+            ReferencedContainer = "container:XcodeProj.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </LaunchAction>
+   <ProfileAction
+      buildConfiguration = "Release"
+      shouldUseLaunchSchemeArgsEnv = "YES"
+      useCustomWorkingDirectory = "NO"
+      debugDocumentVersioning = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+            BuildableName = "XcodeProj.app"
+            BlueprintName = "XcodeProj"
+            ReferencedContainer = "container:XcodeProj.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </ProfileAction>
+   <AnalyzeAction
+      buildConfiguration = "Debug">
+   </AnalyzeAction>
+   <ArchiveAction
+      buildConfiguration = "Release"
+      revealArchiveInOrganizer = "YES">
+   </ArchiveAction>
+</Scheme>
+`
@@ -0,0 +1,274 @@
+package xcodeproj
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+const defaultShellPath = "/bin/sh"
+
+// ShellScriptBuildPhase represents a PBXShellScriptBuildPhase element (a Run Script phase)
+type ShellScriptBuildPhase struct {
+	ID string
+	// Name is the phase's name in Xcode, Xcode displays "Run Script" if it is empty.
+	Name string
+	// ShellPath defaults to /bin/sh when adding the phase.
+	ShellPath           string
+	ShellScript         string
+	InputPaths          []string
+	OutputPaths         []string
+	InputFileListPaths  []string
+	OutputFileListPaths []string
+	// ShowEnvVarsInLog is the "Show environment variables in build log" option,
+	// nil means Xcode's default: the environment variables are shown.
+	ShowEnvVarsInLog *bool
+	// AlwaysOutOfDate is true if the "Based on dependency analysis" option is unchecked.
+	AlwaysOutOfDate bool
+	// RunOnlyForDeploymentPostprocessing is the "For install builds only" option.
+	RunOnlyForDeploymentPostprocessing bool
+}
+
+func newShellScriptBuildPhase(buildPhase BuildPhase) ShellScriptBuildPhase {
+	var showEnvVarsInLog *bool
+	if !buildPhase.ShowEnvVarsInLog {
+		showEnvVarsInLog = &buildPhase.ShowEnvVarsInLog
+	}
+
+	return ShellScriptBuildPhase{
+		ID:                                 buildPhase.ID,
+		Name:                               buildPhase.Name,
+		ShellPath:                          buildPhase.ShellPath,
+		ShellScript:                        buildPhase.ShellScript,
+		InputPaths:                         buildPhase.InputPaths,
+		OutputPaths:                        buildPhase.OutputPaths,
+		InputFileListPaths:                 buildPhase.InputFileListPaths,
+		OutputFileListPaths:                buildPhase.OutputFileListPaths,
+		ShowEnvVarsInLog:                   showEnvVarsInLog,
+		AlwaysOutOfDate:                    buildPhase.AlwaysOutOfDate,
+		RunOnlyForDeploymentPostprocessing: buildPhase.RunOnlyForDeploymentPostprocessing,
+	}
+}
+
+// apply writes the phase's properties to the raw PBXShellScriptBuildPhase object,
+// the properties without Xcode's default values are removed (like alwaysOutOfDate or showEnvVarsInLog).
+func (s ShellScriptBuildPhase) apply(raw serialized.Object) {
+	if s.Name != "" {
+		raw["name"] = s.Name
+	} else {
+		delete(raw, "name")
+	}
+
+	shellPath := s.ShellPath
+	if shellPath == "" {
+		shellPath = defaultShellPath
+	}
+	raw["shellPath"] = shellPath
+	raw["shellScript"] = s.ShellScript
+
+	raw["inputPaths"] = rawStringSlice(s.InputPaths)
+	raw["outputPaths"] = rawStringSlice(s.OutputPaths)
+	raw["inputFileListPaths"] = rawStringSlice(s.InputFileListPaths)
+	raw["outputFileListPaths"] = rawStringSlice(s.OutputFileListPaths)
+
+	raw["runOnlyForDeploymentPostprocessing"] = rawBool(s.RunOnlyForDeploymentPostprocessing)
+	if s.AlwaysOutOfDate {
+		raw["alwaysOutOfDate"] = rawBool(true)
+	} else {
+		delete(raw, "alwaysOutOfDate")
+	}
+	if s.ShowEnvVarsInLog != nil && !*s.ShowEnvVarsInLog {
+		raw["showEnvVarsInLog"] = rawBool(false)
+	} else {
+		delete(raw, "showEnvVarsInLog")
+	}
+}
+
+// RawObject ...
+func (s ShellScriptBuildPhase) RawObject() (map[string]interface{}, error) {
+	raw := map[string]interface{}{
+		"isa":             string(ShellScriptBuildPhaseType),
+		"buildActionMask": "2147483647",
+		"files":           []interface{}{},
+	}
+	s.apply(raw)
+	return raw, nil
+}
+
+func rawStringSlice(values []string) []interface{} {
+	raw := []interface{}{}
+	for _, value := range values {
+		raw = append(raw, value)
+	}
+	return raw
+}
+
+func rawBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// ShellScriptBuildPhases returns the Run Script phases of the target in build order.
+func (p XcodeProj) ShellScriptBuildPhases(targetName string) ([]ShellScriptBuildPhase, error) {
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return nil, fmt.Errorf("failed to find target with name: %s", targetName)
+	}
+
+	var phases []ShellScriptBuildPhase
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type == ShellScriptBuildPhaseType {
+			phases = append(phases, newShellScriptBuildPhase(buildPhase))
+		}
+	}
+	return phases, nil
+}
+
+// AddShellScriptBuildPhase adds a new Run Script phase as the last build phase of the target.
+// The phase's ID is ignored, the ID of the new phase is returned.
+func (p *XcodeProj) AddShellScriptBuildPhase(targetName string, phase ShellScriptBuildPhase) (string, error) {
+	rawTarget, err := p.rawTargetByName(targetName)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := p.InsertObject(phase)
+	if err != nil {
+		return "", err
+	}
+
+	buildPhaseIDs, err := rawTarget.StringSlice("buildPhases")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return "", err
+	}
+	rawTarget["buildPhases"] = rawStringSlice(append(buildPhaseIDs, id))
+
+	return id, p.reloadProj()
+}
+
+// UpdateShellScriptBuildPhase overrides the properties of the Run Script phase with the given ID.
+func (p *XcodeProj) UpdateShellScriptBuildPhase(phase ShellScriptBuildPhase) error {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+
+	raw, err := objects.Object(phase.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find build phase (%s): %s", phase.ID, err)
+	}
+	if isa, err := raw.String("isa"); err != nil {
+		return err
+	} else if isa != string(ShellScriptBuildPhaseType) {
+		return fmt.Errorf("build phase (%s) is not a %s element", phase.ID, ShellScriptBuildPhaseType)
+	}
+
+	phase.apply(raw)
+
+	return p.reloadProj()
+}
+
+// MoveBuildPhase moves the target's build phase to the given index of the target's build phases.
+func (p *XcodeProj) MoveBuildPhase(targetName, buildPhaseID string, index int) error {
+	rawTarget, err := p.rawTargetByName(targetName)
+	if err != nil {
+		return err
+	}
+
+	buildPhaseIDs, err := rawTarget.StringSlice("buildPhases")
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(buildPhaseIDs) {
+		return fmt.Errorf("build phase index (%d) out of range [0, %d)", index, len(buildPhaseIDs))
+	}
+
+	var reordered []string
+	found := false
+	for _, id := range buildPhaseIDs {
+		if id == buildPhaseID {
+			found = true
+			continue
+		}
+		reordered = append(reordered, id)
+	}
+	if !found {
+		return fmt.Errorf("target (%s) has no build phase with id: %s", targetName, buildPhaseID)
+	}
+
+	reordered = append(reordered[:index], append([]string{buildPhaseID}, reordered[index:]...)...)
+	rawTarget["buildPhases"] = rawStringSlice(reordered)
+
+	return p.reloadProj()
+}
+
+// RemoveShellScriptBuildPhase removes the Run Script phase from the target,
+// the phase object is removed from the project if no other target uses it.
+func (p *XcodeProj) RemoveShellScriptBuildPhase(targetName, buildPhaseID string) error {
+	rawTarget, err := p.rawTargetByName(targetName)
+	if err != nil {
+		return err
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+
+	if raw, err := objects.Object(buildPhaseID); err != nil {
+		return fmt.Errorf("failed to find build phase (%s): %s", buildPhaseID, err)
+	} else if isa, err := raw.String("isa"); err != nil {
+		return err
+	} else if isa != string(ShellScriptBuildPhaseType) {
+		return fmt.Errorf("build phase (%s) is not a %s element", buildPhaseID, ShellScriptBuildPhaseType)
+	}
+
+	buildPhaseIDs, err := rawTarget.StringSlice("buildPhases")
+	if err != nil {
+		return err
+	}
+
+	var remaining []string
+	for _, id := range buildPhaseIDs {
+		if id != buildPhaseID {
+			remaining = append(remaining, id)
+		}
+	}
+	if len(remaining) == len(buildPhaseIDs) {
+		return fmt.Errorf("target (%s) has no build phase with id: %s", targetName, buildPhaseID)
+	}
+	rawTarget["buildPhases"] = rawStringSlice(remaining)
+
+	used := false
+	for _, target := range p.Proj.Targets {
+		if target.Name == targetName {
+			continue
+		}
+		for _, id := range target.buildPhaseIDs {
+			if id == buildPhaseID {
+				used = true
+			}
+		}
+	}
+	if !used {
+		delete(objects, buildPhaseID)
+	}
+
+	return p.reloadProj()
+}
+
+func (p XcodeProj) rawTargetByName(targetName string) (serialized.Object, error) {
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return nil, fmt.Errorf("failed to find target with name: %s", targetName)
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	return objects.Object(target.ID)
+}
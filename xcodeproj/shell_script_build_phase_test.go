@@ -0,0 +1,114 @@
+package xcodeproj
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_ShellScriptBuildPhases(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	t.Log("add")
+	id, err := proj.AddShellScriptBuildPhase("XcodeProj", ShellScriptBuildPhase{
+		Name:               "Upload dSYMs",
+		ShellScript:        "\"${PODS_ROOT}/FirebaseCrashlytics/run\"\n",
+		InputPaths:         []string{"${DWARF_DSYM_FOLDER_PATH}/${DWARF_DSYM_FILE_NAME}"},
+		InputFileListPaths: []string{"$(SRCROOT)/inputs.xcfilelist"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 24, len(id))
+
+	phases, err := proj.ShellScriptBuildPhases("XcodeProj")
+	require.NoError(t, err)
+	require.Equal(t, []ShellScriptBuildPhase{{
+		ID:                  id,
+		Name:                "Upload dSYMs",
+		ShellPath:           "/bin/sh",
+		ShellScript:         "\"${PODS_ROOT}/FirebaseCrashlytics/run\"\n",
+		InputPaths:          []string{"${DWARF_DSYM_FOLDER_PATH}/${DWARF_DSYM_FILE_NAME}"},
+		OutputPaths:         []string{},
+		InputFileListPaths:  []string{"$(SRCROOT)/inputs.xcfilelist"},
+		OutputFileListPaths: []string{},
+	}}, phases)
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, 5, len(target.BuildPhases()))
+	require.Equal(t, id, target.BuildPhases()[4].ID)
+
+	content, err := proj.perObjectModify()
+	require.NoError(t, err)
+	require.Contains(t, string(content), `/* Begin PBXShellScriptBuildPhase section */
+		`+id+` /* Upload dSYMs */ = {
+			isa = PBXShellScriptBuildPhase;
+			buildActionMask = 2147483647;
+			files = (
+			);
+			inputFileListPaths = (
+				"$(SRCROOT)/inputs.xcfilelist",
+			);
+			inputPaths = (
+				"${DWARF_DSYM_FOLDER_PATH}/${DWARF_DSYM_FILE_NAME}",
+			);
+			name = "Upload dSYMs";
+			outputFileListPaths = (
+			);
+			outputPaths = (
+			);
+			runOnlyForDeploymentPostprocessing = 0;
+			shellPath = /bin/sh;
+			shellScript = "\"${PODS_ROOT}/FirebaseCrashlytics/run\"\n";
+		};
+/* End PBXShellScriptBuildPhase section */
+`)
+
+	t.Log("update")
+	phase := phases[0]
+	phase.ShellScript = "echo \"updated\"\n"
+	phase.AlwaysOutOfDate = true
+	showEnvVarsInLog := false
+	phase.ShowEnvVarsInLog = &showEnvVarsInLog
+	require.NoError(t, proj.UpdateShellScriptBuildPhase(phase))
+
+	phases, err = proj.ShellScriptBuildPhases("XcodeProj")
+	require.NoError(t, err)
+	require.Equal(t, phase, phases[0])
+
+	content, err = proj.perObjectModify()
+	require.NoError(t, err)
+	require.Contains(t, string(content), "showEnvVarsInLog = 0;")
+
+	showEnvVarsInLog = true
+	require.NoError(t, proj.UpdateShellScriptBuildPhase(phase))
+	phases, err = proj.ShellScriptBuildPhases("XcodeProj")
+	require.NoError(t, err)
+	require.Nil(t, phases[0].ShowEnvVarsInLog)
+
+	t.Log("move")
+	require.NoError(t, proj.MoveBuildPhase("XcodeProj", id, 0))
+	target, ok = proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, id, target.BuildPhases()[0].ID)
+	require.Equal(t, SourcesBuildPhaseType, target.BuildPhases()[1].Type)
+
+	require.Error(t, proj.MoveBuildPhase("XcodeProj", id, 5))
+	require.Error(t, proj.MoveBuildPhase("XcodeProj", "7D0342ED20F4BA280050B6A6", 0))
+
+	t.Log("remove")
+	require.Error(t, proj.RemoveShellScriptBuildPhase("XcodeProj", "7D5B35F820E28EE80022BAE6"))
+	require.NoError(t, proj.RemoveShellScriptBuildPhase("XcodeProj", id))
+
+	phases, err = proj.ShellScriptBuildPhases("XcodeProj")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(phases))
+
+	content, err = proj.perObjectModify()
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(content), id))
+	require.Equal(t, testhelper.XcodeProjectTest, string(content))
+}
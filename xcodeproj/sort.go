@@ -0,0 +1,220 @@
+package xcodeproj
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// Sort normalizes the project like xUnique and sort-Xcode-project-file do, to shrink the diffs of the project.pbxproj:
+// the children of the groups are sorted folders first, then by name, the files of the Sources build phases by name
+// and the objects by ID within their section.
+// The project is parsed again from the normalized contents, Save writes these contents.
+// Sort is idempotent, it reports whether the contents changed.
+func (p *XcodeProj) Sort() (bool, error) {
+	before, err := p.pbxProjContents()
+	if err != nil {
+		return false, err
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return false, err
+	}
+
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil {
+			return false, err
+		}
+		isa, err := object.String("isa")
+		if err != nil {
+			return false, err
+		}
+
+		switch isa {
+		case string(GroupElementType):
+			if err := sortObjectIDList(object, "children", objects, groupChildSortKey); err != nil {
+				return false, err
+			}
+		case string(SourcesBuildPhaseType):
+			if err := sortObjectIDList(object, "files", objects, buildFileSortKey); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	after, err := p.pbxProjContents()
+	if err != nil {
+		return false, err
+	}
+	after, err = sortObjectSections(after)
+	if err != nil {
+		return false, err
+	}
+
+	sorted, err := parsePBXProjContent(after)
+	if err != nil {
+		return false, err
+	}
+	// only the parsed project is replaced, the settings and the queued scheme changes are kept
+	p.Proj = sorted.Proj
+	p.RawProj = sorted.RawProj
+	p.Format = sorted.Format
+	p.originalContents = sorted.originalContents
+	p.originalPbxProj = sorted.originalPbxProj
+	p.annotatedPbxProj = sorted.annotatedPbxProj
+
+	return !bytes.Equal(before, after), nil
+}
+
+// sortKey orders the objects: folders first, then by name (case-insensitively), then by ID.
+type sortKey struct {
+	folder bool
+	name   string
+	id     string
+}
+
+func (k sortKey) less(other sortKey) bool {
+	if k.folder != other.folder {
+		return k.folder
+	}
+	if lower, otherLower := strings.ToLower(k.name), strings.ToLower(other.name); lower != otherLower {
+		return lower < otherLower
+	}
+	if k.name != other.name {
+		return k.name < other.name
+	}
+	return k.id < other.id
+}
+
+func sortObjectIDList(object serialized.Object, key string, objects serialized.Object, keyOf func(id string, objects serialized.Object) sortKey) error {
+	ids, err := object.StringSlice(key)
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	keys := map[string]sortKey{}
+	for _, id := range ids {
+		keys[id] = keyOf(id, objects)
+	}
+	sorted := append([]string{}, ids...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return keys[sorted[i]].less(keys[sorted[j]])
+	})
+
+	for i := range ids {
+		if ids[i] != sorted[i] {
+			object[key] = rawStringSlice(sorted)
+			break
+		}
+	}
+	return nil
+}
+
+func groupChildSortKey(id string, objects serialized.Object) sortKey {
+	object, err := objects.Object(id)
+	if err != nil {
+		return sortKey{id: id}
+	}
+	isa, _ := object.String("isa")
+	return sortKey{folder: isa == string(GroupElementType), name: rawDisplayName(object), id: id}
+}
+
+func buildFileSortKey(id string, objects serialized.Object) sortKey {
+	object, err := objects.Object(id)
+	if err != nil {
+		return sortKey{id: id}
+	}
+
+	name := ""
+	if fileRef, err := object.String("fileRef"); err == nil {
+		if element, err := objects.Object(fileRef); err == nil {
+			name = rawDisplayName(element)
+		}
+	} else if productRef, err := object.String("productRef"); err == nil {
+		if product, err := objects.Object(productRef); err == nil {
+			name, _ = product.String("productName")
+		}
+	}
+	return sortKey{name: name, id: id}
+}
+
+// rawDisplayName returns the name Xcode displays for the file tree element: its name or the last component of its path.
+func rawDisplayName(object serialized.Object) string {
+	if name, err := object.String("name"); err == nil && name != "" {
+		return name
+	}
+	if pth, err := object.String("path"); err == nil && pth != "" {
+		return path.Base(pth)
+	}
+	return ""
+}
+
+// sortObjectSections orders the object entries by ID within each section of the pbxproj contents.
+// A section is left unchanged if it contains anything besides the object entries.
+func sortObjectSections(contents []byte) ([]byte, error) {
+	proj, err := parsePBXProjContent(contents)
+	if err != nil {
+		return nil, err
+	}
+	objectsAnnotated, err := proj.annotatedPbxProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+	sections, err := findObjectSections(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		id         string
+		start, end int
+	}
+	entriesBySection := make([][]entry, len(sections))
+	for id := range objectsAnnotated {
+		start, end, err := objectLineRange(contents, objectsAnnotated, id)
+		if err != nil {
+			continue
+		}
+		for i, section := range sections {
+			if start >= section.start && end <= section.bodyEnd {
+				entriesBySection[i] = append(entriesBySection[i], entry{id: id, start: start, end: end})
+				break
+			}
+		}
+	}
+
+	var sorted []byte
+	previousEnd := 0
+	for i, section := range sections {
+		entries := entriesBySection[i]
+		if len(entries) == 0 {
+			continue
+		}
+		sort.Slice(entries, func(a, b int) bool { return entries[a].start < entries[b].start })
+
+		bodyStart := bytes.IndexByte(contents[section.start:], '\n') + section.start + 1
+		contiguous := entries[0].start == bodyStart && entries[len(entries)-1].end == section.bodyEnd
+		for j := 1; contiguous && j < len(entries); j++ {
+			contiguous = entries[j].start == entries[j-1].end
+		}
+		if !contiguous {
+			continue
+		}
+
+		sort.Slice(entries, func(a, b int) bool { return entries[a].id < entries[b].id })
+		sorted = append(sorted, contents[previousEnd:bodyStart]...)
+		for _, e := range entries {
+			sorted = append(sorted, contents[e.start:e.end]...)
+		}
+		previousEnd = section.bodyEnd
+	}
+	return append(sorted, contents[previousEnd:]...), nil
+}
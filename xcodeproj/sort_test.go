@@ -0,0 +1,98 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_Sort(t *testing.T) {
+	// move a build file to the end of its section
+	line := "\t\t7D03431A20F4BB070050B6A6 /* TodayExtension.appex in Embed App Extensions */ = {isa = PBXBuildFile; fileRef = 7D03430D20F4BB070050B6A6 /* TodayExtension.appex */; settings = {ATTRIBUTES = (RemoveHeadersOnCopy, ); }; };\n"
+	content := strings.Replace(testhelper.XcodeProjectTest, line, "", 1)
+	content = strings.Replace(content, "/* End PBXBuildFile section */", line+"/* End PBXBuildFile section */", 1)
+
+	proj, err := parsePBXProjContent([]byte(content))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+	proj.IDGenerator = NewSeededObjectIDGenerator(1)
+
+	groupID, err := proj.AddGroup("7D5B35FE20E28EE80022BAE6", Group{FileElementBase: FileElementBase{Path: "Views"}})
+	require.NoError(t, err)
+	fileID, err := proj.AddFileReference("7D5B35FE20E28EE80022BAE6", FileReference{FileElementBase: FileElementBase{Path: "Base.swift"}})
+	require.NoError(t, err)
+	buildFileIDs, err := proj.AddFileToTargets(fileID, SourcesBuildPhaseType, "XcodeProj")
+	require.NoError(t, err)
+
+	changed, err := proj.Sort()
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+	group, err := objects.Object("7D5B35FE20E28EE80022BAE6")
+	require.NoError(t, err)
+	children, err := group.StringSlice("children")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		groupID,
+		"7D5B35FF20E28EE80022BAE6", // AppDelegate.swift
+		"7D5B360620E28EEA0022BAE6", // Assets.xcassets
+		fileID,                     // Base.swift
+		"7D5B360B20E28EEA0022BAE6", // Info.plist
+		"7D5B360820E28EEA0022BAE6", // LaunchScreen.storyboard
+		"7D5B360320E28EE80022BAE6", // Main.storyboard
+		"7D5B360120E28EE80022BAE6", // ViewController.swift
+	}, children)
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type != SourcesBuildPhaseType {
+			continue
+		}
+		var ids []string
+		for _, file := range buildPhase.Files {
+			ids = append(ids, file.ID)
+		}
+		require.Equal(t, []string{"7D5B360020E28EE80022BAE6", buildFileIDs[0], "7D5B360220E28EE80022BAE6"}, ids)
+	}
+
+	sorted, err := proj.perObjectModify()
+	require.NoError(t, err)
+	require.Contains(t, string(sorted), "\t\t7D03432120F4BB8D0050B6A6 /* CloudKit.framework in Frameworks */ = {isa = PBXBuildFile; fileRef = 7D03432020F4BB8D0050B6A6 /* CloudKit.framework */; };\n")
+	require.True(t, strings.Index(string(sorted), line) < strings.Index(string(sorted), "\t\t7D03432120F4BB8D0050B6A6 /* CloudKit.framework in Frameworks */"))
+
+	changed, err = proj.Sort()
+	require.NoError(t, err)
+	require.False(t, changed)
+	resorted, err := proj.perObjectModify()
+	require.NoError(t, err)
+	require.Equal(t, string(sorted), string(resorted))
+}
+
+func TestXcodeProj_Sort_KeepsSchemeChanges(t *testing.T) {
+	dir := t.TempDir()
+	projectPth := filepath.Join(dir, "XcodeProj.xcodeproj")
+	schemePth := filepath.Join(projectPth, "xcshareddata", "xcschemes", "TodayExtension.xcscheme")
+	require.NoError(t, os.MkdirAll(filepath.Dir(schemePth), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(testhelper.XcodeProjectTest), 0644))
+	require.NoError(t, ioutil.WriteFile(schemePth, []byte(removeTargetTestScheme("7D03430C20F4BB070050B6A6", "container:XcodeProj.xcodeproj")), 0644))
+
+	proj, err := Open(projectPth)
+	require.NoError(t, err)
+	require.NoError(t, proj.RemoveTarget("TodayExtension", true))
+
+	_, err = proj.Sort()
+	require.NoError(t, err)
+	require.Equal(t, projectPth, proj.Path)
+
+	require.NoError(t, proj.Save())
+	_, err = os.Stat(schemePth)
+	require.True(t, os.IsNotExist(err))
+}
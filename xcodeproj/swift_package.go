@@ -0,0 +1,455 @@
+package xcodeproj
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// SwiftPackageReferenceType ...
+type SwiftPackageReferenceType string
+
+// SwiftPackageReferenceTypes
+const (
+	RemoteSwiftPackageReferenceType SwiftPackageReferenceType = "XCRemoteSwiftPackageReference"
+	LocalSwiftPackageReferenceType  SwiftPackageReferenceType = "XCLocalSwiftPackageReference"
+)
+
+const swiftPackageProductDependencyType = "XCSwiftPackageProductDependency"
+
+// SwiftPackageRequirementKind is the kind of a remote Swift package's version requirement.
+type SwiftPackageRequirementKind string
+
+// SwiftPackageRequirementKinds
+const (
+	UpToNextMajorVersionRequirementKind SwiftPackageRequirementKind = "upToNextMajorVersion"
+	UpToNextMinorVersionRequirementKind SwiftPackageRequirementKind = "upToNextMinorVersion"
+	ExactVersionRequirementKind         SwiftPackageRequirementKind = "exactVersion"
+	VersionRangeRequirementKind         SwiftPackageRequirementKind = "versionRange"
+	BranchRequirementKind               SwiftPackageRequirementKind = "branch"
+	RevisionRequirementKind             SwiftPackageRequirementKind = "revision"
+)
+
+// SwiftPackageRequirement is the version requirement of a remote Swift package,
+// the bounds used depend on the Kind:
+// upToNextMajorVersion and upToNextMinorVersion: MinimumVersion,
+// versionRange: MinimumVersion and MaximumVersion,
+// exactVersion: Version, branch: Branch, revision: Revision.
+type SwiftPackageRequirement struct {
+	Kind           SwiftPackageRequirementKind
+	MinimumVersion string
+	MaximumVersion string
+	Version        string
+	Branch         string
+	Revision       string
+}
+
+// SwiftPackageReference represents an XCRemoteSwiftPackageReference or an XCLocalSwiftPackageReference element
+type SwiftPackageReference struct {
+	Type SwiftPackageReferenceType
+	ID   string
+
+	// XCRemoteSwiftPackageReference properties
+	RepositoryURL string
+	Requirement   SwiftPackageRequirement
+
+	// XCLocalSwiftPackageReference properties
+	RelativePath string
+}
+
+// SwiftPackageProductDependency represents an XCSwiftPackageProductDependency element, a package product a target depends on.
+type SwiftPackageProductDependency struct {
+	ID          string
+	ProductName string
+	// PackageID is the ID of the package reference, it is empty for the local packages of the older projects.
+	PackageID string
+}
+
+func parseSwiftPackageRequirement(raw serialized.Object) (SwiftPackageRequirement, error) {
+	kind, err := raw.String("kind")
+	if err != nil {
+		return SwiftPackageRequirement{}, err
+	}
+
+	requirement := SwiftPackageRequirement{Kind: SwiftPackageRequirementKind(kind)}
+	properties := map[string]*string{
+		"minimumVersion": &requirement.MinimumVersion,
+		"maximumVersion": &requirement.MaximumVersion,
+		"version":        &requirement.Version,
+		"branch":         &requirement.Branch,
+		"revision":       &requirement.Revision,
+	}
+	for key, value := range properties {
+		if *value, err = raw.String(key); err != nil && !serialized.IsKeyNotFoundError(err) {
+			return SwiftPackageRequirement{}, err
+		}
+	}
+
+	return requirement, nil
+}
+
+func (r SwiftPackageRequirement) raw() (map[string]interface{}, error) {
+	required := map[SwiftPackageRequirementKind]map[string]string{
+		UpToNextMajorVersionRequirementKind: {"minimumVersion": r.MinimumVersion},
+		UpToNextMinorVersionRequirementKind: {"minimumVersion": r.MinimumVersion},
+		ExactVersionRequirementKind:         {"version": r.Version},
+		VersionRangeRequirementKind:         {"minimumVersion": r.MinimumVersion, "maximumVersion": r.MaximumVersion},
+		BranchRequirementKind:               {"branch": r.Branch},
+		RevisionRequirementKind:             {"revision": r.Revision},
+	}
+
+	properties, ok := required[r.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown Swift package requirement kind: %s", r.Kind)
+	}
+
+	raw := map[string]interface{}{"kind": string(r.Kind)}
+	for key, value := range properties {
+		if value == "" {
+			return nil, fmt.Errorf("%s requirement needs %s", r.Kind, key)
+		}
+		raw[key] = value
+	}
+	return raw, nil
+}
+
+// RawObject ...
+func (r SwiftPackageReference) RawObject() (map[string]interface{}, error) {
+	switch r.Type {
+	case RemoteSwiftPackageReferenceType:
+		rawRequirement, err := r.Requirement.raw()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"isa":           string(r.Type),
+			"repositoryURL": r.RepositoryURL,
+			"requirement":   rawRequirement,
+		}, nil
+	case LocalSwiftPackageReferenceType:
+		return map[string]interface{}{
+			"isa":          string(r.Type),
+			"relativePath": r.RelativePath,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown Swift package reference type: %s", r.Type)
+	}
+}
+
+// RawObject ...
+func (d SwiftPackageProductDependency) RawObject() (map[string]interface{}, error) {
+	raw := map[string]interface{}{
+		"isa":         swiftPackageProductDependencyType,
+		"productName": d.ProductName,
+	}
+	if d.PackageID != "" {
+		raw["package"] = d.PackageID
+	}
+	return raw, nil
+}
+
+func parseSwiftPackageReference(id string, objects serialized.Object) (SwiftPackageReference, error) {
+	rawReference, err := objects.Object(id)
+	if err != nil {
+		return SwiftPackageReference{}, err
+	}
+
+	isa, err := rawReference.String("isa")
+	if err != nil {
+		return SwiftPackageReference{}, err
+	}
+
+	reference := SwiftPackageReference{
+		Type: SwiftPackageReferenceType(isa),
+		ID:   id,
+	}
+
+	switch reference.Type {
+	case RemoteSwiftPackageReferenceType:
+		if reference.RepositoryURL, err = rawReference.String("repositoryURL"); err != nil {
+			return SwiftPackageReference{}, err
+		}
+
+		rawRequirement, err := rawReference.Object("requirement")
+		if err != nil {
+			if serialized.IsKeyNotFoundError(err) {
+				return reference, nil
+			}
+			return SwiftPackageReference{}, err
+		}
+		if reference.Requirement, err = parseSwiftPackageRequirement(rawRequirement); err != nil {
+			return SwiftPackageReference{}, fmt.Errorf("failed to parse requirement of package (%s): %s", reference.RepositoryURL, err)
+		}
+	case LocalSwiftPackageReferenceType:
+		if reference.RelativePath, err = rawReference.String("relativePath"); err != nil {
+			return SwiftPackageReference{}, err
+		}
+	default:
+		return SwiftPackageReference{}, fmt.Errorf("not a Swift package reference element: %s", isa)
+	}
+
+	return reference, nil
+}
+
+func parseSwiftPackageProductDependency(id string, objects serialized.Object) (SwiftPackageProductDependency, error) {
+	rawDependency, err := objects.Object(id)
+	if err != nil {
+		return SwiftPackageProductDependency{}, err
+	}
+
+	if isa, err := rawDependency.String("isa"); err != nil {
+		return SwiftPackageProductDependency{}, err
+	} else if isa != swiftPackageProductDependencyType {
+		return SwiftPackageProductDependency{}, fmt.Errorf("not a %s element", swiftPackageProductDependencyType)
+	}
+
+	productName, err := rawDependency.String("productName")
+	if err != nil {
+		return SwiftPackageProductDependency{}, err
+	}
+
+	packageID, err := rawDependency.String("package")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return SwiftPackageProductDependency{}, err
+	}
+
+	return SwiftPackageProductDependency{
+		ID:          id,
+		ProductName: productName,
+		PackageID:   packageID,
+	}, nil
+}
+
+// AddRemoteSwiftPackage adds a remote package reference to the project and returns its ID.
+func (p *XcodeProj) AddRemoteSwiftPackage(repositoryURL string, requirement SwiftPackageRequirement) (string, error) {
+	for _, reference := range p.Proj.SwiftPackageReferences() {
+		if reference.Type == RemoteSwiftPackageReferenceType && reference.RepositoryURL == repositoryURL {
+			return "", fmt.Errorf("package already added: %s", repositoryURL)
+		}
+	}
+
+	return p.addSwiftPackageReference(SwiftPackageReference{
+		Type:          RemoteSwiftPackageReferenceType,
+		RepositoryURL: repositoryURL,
+		Requirement:   requirement,
+	})
+}
+
+// AddLocalSwiftPackage adds a local package reference (relative to the project's directory) to the project and returns its ID.
+func (p *XcodeProj) AddLocalSwiftPackage(relativePath string) (string, error) {
+	for _, reference := range p.Proj.SwiftPackageReferences() {
+		if reference.Type == LocalSwiftPackageReferenceType && reference.RelativePath == relativePath {
+			return "", fmt.Errorf("package already added: %s", relativePath)
+		}
+	}
+
+	return p.addSwiftPackageReference(SwiftPackageReference{
+		Type:         LocalSwiftPackageReferenceType,
+		RelativePath: relativePath,
+	})
+}
+
+func (p *XcodeProj) addSwiftPackageReference(reference SwiftPackageReference) (string, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+
+	project, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := p.InsertObject(reference)
+	if err != nil {
+		return "", err
+	}
+
+	packageReferences, err := project.StringSlice("packageReferences")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return "", err
+	}
+	project["packageReferences"] = rawStringSlice(append(packageReferences, id))
+
+	return id, p.reloadProj()
+}
+
+// AddSwiftPackageProduct links the package's product to the target and returns the ID of the product dependency.
+// The product is added to the target's Frameworks build phase, if the target has one.
+func (p *XcodeProj) AddSwiftPackageProduct(targetName, packageID, productName string) (string, error) {
+	if _, ok := p.Proj.SwiftPackageReference(packageID); !ok {
+		return "", fmt.Errorf("failed to find package with id: %s", packageID)
+	}
+
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return "", fmt.Errorf("failed to find target with name: %s", targetName)
+	}
+	for _, dependency := range target.SwiftPackageProductDependencies() {
+		if dependency.PackageID == packageID && dependency.ProductName == productName {
+			return "", fmt.Errorf("target (%s) already depends on product: %s", targetName, productName)
+		}
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return "", err
+	}
+	rawTarget, err := objects.Object(target.ID)
+	if err != nil {
+		return "", err
+	}
+
+	dependencyID, err := p.InsertObject(SwiftPackageProductDependency{ProductName: productName, PackageID: packageID})
+	if err != nil {
+		return "", err
+	}
+
+	dependencyIDs, err := rawTarget.StringSlice("packageProductDependencies")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return "", err
+	}
+	rawTarget["packageProductDependencies"] = rawStringSlice(append(dependencyIDs, dependencyID))
+
+	for _, buildPhase := range target.BuildPhases() {
+		if buildPhase.Type != FrameworksBuildPhaseType {
+			continue
+		}
+
+		rawBuildPhase, err := objects.Object(buildPhase.ID)
+		if err != nil {
+			return "", err
+		}
+
+		buildFileID, err := p.InsertObject(BuildFile{ProductRef: dependencyID})
+		if err != nil {
+			return "", err
+		}
+
+		files, err := rawBuildPhase.StringSlice("files")
+		if err != nil && !serialized.IsKeyNotFoundError(err) {
+			return "", err
+		}
+		rawBuildPhase["files"] = rawStringSlice(append(files, buildFileID))
+		break
+	}
+
+	return dependencyID, p.reloadProj()
+}
+
+// UpdateSwiftPackageRequirement changes the version requirement of the remote package.
+func (p *XcodeProj) UpdateSwiftPackageRequirement(packageID string, requirement SwiftPackageRequirement) error {
+	reference, ok := p.Proj.SwiftPackageReference(packageID)
+	if !ok {
+		return fmt.Errorf("failed to find package with id: %s", packageID)
+	}
+	if reference.Type != RemoteSwiftPackageReferenceType {
+		return fmt.Errorf("package (%s) is not a %s", packageID, RemoteSwiftPackageReferenceType)
+	}
+
+	rawRequirement, err := requirement.raw()
+	if err != nil {
+		return err
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+	rawReference, err := objects.Object(packageID)
+	if err != nil {
+		return err
+	}
+	rawReference["requirement"] = rawRequirement
+
+	return p.reloadProj()
+}
+
+// RemoveSwiftPackage removes the package reference from the project,
+// together with the targets' product dependencies on the package and their build files.
+func (p *XcodeProj) RemoveSwiftPackage(packageID string) error {
+	if _, ok := p.Proj.SwiftPackageReference(packageID); !ok {
+		return fmt.Errorf("failed to find package with id: %s", packageID)
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+
+	project, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return err
+	}
+	if err := removeIDFromList(project, "packageReferences", map[string]bool{packageID: true}); err != nil {
+		return err
+	}
+	delete(objects, packageID)
+
+	removedDependencies := map[string]bool{}
+	for _, target := range p.Proj.Targets {
+		for _, dependency := range target.SwiftPackageProductDependencies() {
+			if dependency.PackageID == packageID {
+				removedDependencies[dependency.ID] = true
+			}
+		}
+	}
+
+	removedBuildFiles := map[string]bool{}
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil {
+			return err
+		}
+		if isa, err := object.String("isa"); err != nil || isa != "PBXBuildFile" {
+			continue
+		}
+		if productRef, err := object.String("productRef"); err == nil && removedDependencies[productRef] {
+			removedBuildFiles[id] = true
+		}
+	}
+
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil {
+			return err
+		}
+		if err := removeIDFromList(object, "packageProductDependencies", removedDependencies); err != nil {
+			return err
+		}
+		if err := removeIDFromList(object, "files", removedBuildFiles); err != nil {
+			return err
+		}
+	}
+
+	for id := range removedDependencies {
+		delete(objects, id)
+	}
+	for id := range removedBuildFiles {
+		delete(objects, id)
+	}
+
+	return p.reloadProj()
+}
+
+// removeIDFromList removes the given IDs from the object's ID list property, if the object has the property.
+func removeIDFromList(object serialized.Object, key string, ids map[string]bool) error {
+	list, err := object.StringSlice(key)
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	var remaining []string
+	for _, id := range list {
+		if !ids[id] {
+			remaining = append(remaining, id)
+		}
+	}
+	if len(remaining) != len(list) {
+		object[key] = rawStringSlice(remaining)
+	}
+	return nil
+}
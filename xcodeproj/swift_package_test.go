@@ -0,0 +1,202 @@
+package xcodeproj
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSwiftPackageReference(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawSwiftPackages), &raw)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		id      string
+		want    SwiftPackageReference
+		wantErr bool
+	}{
+		{
+			name: "remote package",
+			id:   "4F0A5A3B2A6E6C2E00B6C2A1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "4F0A5A3B2A6E6C2E00B6C2A1",
+				RepositoryURL: "https://github.com/Alamofire/Alamofire.git",
+				Requirement:   SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.8.0"},
+			},
+		},
+		{
+			name: "remote package with version range",
+			id:   "4F0A5A3C2A6E6C2E00B6C2A1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "4F0A5A3C2A6E6C2E00B6C2A1",
+				RepositoryURL: "https://github.com/firebase/firebase-ios-sdk",
+				Requirement:   SwiftPackageRequirement{Kind: VersionRangeRequirementKind, MinimumVersion: "10.0.0", MaximumVersion: "11.0.0"},
+			},
+		},
+		{
+			name: "local package",
+			id:   "4F0A5A3D2A6E6C2E00B6C2A1",
+			want: SwiftPackageReference{
+				Type:         LocalSwiftPackageReferenceType,
+				ID:           "4F0A5A3D2A6E6C2E00B6C2A1",
+				RelativePath: "../Packages/Core",
+			},
+		},
+		{
+			name:    "not a package reference",
+			id:      "4F0A5A3E2A6E6C2E00B6C2A1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSwiftPackageReference(tt.id, raw)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseSwiftPackageProductDependency(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawSwiftPackages), &raw)
+	require.NoError(t, err)
+
+	got, err := parseSwiftPackageProductDependency("4F0A5A3E2A6E6C2E00B6C2A1", raw)
+	require.NoError(t, err)
+	require.Equal(t, SwiftPackageProductDependency{ID: "4F0A5A3E2A6E6C2E00B6C2A1", ProductName: "Alamofire", PackageID: "4F0A5A3B2A6E6C2E00B6C2A1"}, got)
+
+	got, err = parseSwiftPackageProductDependency("4F0A5A3F2A6E6C2E00B6C2A1", raw)
+	require.NoError(t, err)
+	require.Equal(t, SwiftPackageProductDependency{ID: "4F0A5A3F2A6E6C2E00B6C2A1", ProductName: "Core"}, got)
+}
+
+func TestSwiftPackageRequirement_raw(t *testing.T) {
+	raw, err := SwiftPackageRequirement{Kind: ExactVersionRequirementKind, Version: "1.2.3"}.raw()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"kind": "exactVersion", "version": "1.2.3"}, raw)
+
+	_, err = SwiftPackageRequirement{Kind: BranchRequirementKind}.raw()
+	require.EqualError(t, err, "branch requirement needs branch")
+
+	_, err = SwiftPackageRequirement{Kind: "latest"}.raw()
+	require.EqualError(t, err, "unknown Swift package requirement kind: latest")
+}
+
+func TestXcodeProj_SwiftPackages(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	t.Log("add package")
+	packageID, err := proj.AddRemoteSwiftPackage("https://github.com/Alamofire/Alamofire.git", SwiftPackageRequirement{
+		Kind:           UpToNextMajorVersionRequirementKind,
+		MinimumVersion: "5.8.0",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []SwiftPackageReference{{
+		Type:          RemoteSwiftPackageReferenceType,
+		ID:            packageID,
+		RepositoryURL: "https://github.com/Alamofire/Alamofire.git",
+		Requirement:   SwiftPackageRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.8.0"},
+	}}, proj.Proj.SwiftPackageReferences())
+
+	_, err = proj.AddRemoteSwiftPackage("https://github.com/Alamofire/Alamofire.git", SwiftPackageRequirement{Kind: BranchRequirementKind, Branch: "master"})
+	require.EqualError(t, err, "package already added: https://github.com/Alamofire/Alamofire.git")
+
+	t.Log("add package product")
+	dependencyID, err := proj.AddSwiftPackageProduct("XcodeProj", packageID, "Alamofire")
+	require.NoError(t, err)
+
+	target, ok := proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, []SwiftPackageProductDependency{{ID: dependencyID, ProductName: "Alamofire", PackageID: packageID}}, target.SwiftPackageProductDependencies())
+	frameworks := target.BuildPhases()[1]
+	require.Equal(t, FrameworksBuildPhaseType, frameworks.Type)
+	require.Equal(t, 1, len(frameworks.Files))
+	require.Equal(t, dependencyID, frameworks.Files[0].ProductRef)
+
+	content, err := proj.perObjectModify()
+	require.NoError(t, err)
+	require.Contains(t, string(content), `		`+frameworks.Files[0].ID+` /* Alamofire in Frameworks */ = {isa = PBXBuildFile; productRef = `+dependencyID+` /* Alamofire */; };
+`)
+	require.Contains(t, string(content), `/* Begin XCRemoteSwiftPackageReference section */
+		`+packageID+` /* XCRemoteSwiftPackageReference "Alamofire" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/Alamofire/Alamofire.git";
+			requirement = {
+				kind = upToNextMajorVersion;
+				minimumVersion = 5.8.0;
+			};
+		};
+/* End XCRemoteSwiftPackageReference section */
+`)
+
+	t.Log("update requirement")
+	requirement := SwiftPackageRequirement{Kind: ExactVersionRequirementKind, Version: "5.9.1"}
+	require.NoError(t, proj.UpdateSwiftPackageRequirement(packageID, requirement))
+	reference, ok := proj.Proj.SwiftPackageReference(packageID)
+	require.True(t, ok)
+	require.Equal(t, requirement, reference.Requirement)
+
+	t.Log("remove package")
+	require.NoError(t, proj.RemoveSwiftPackage(packageID))
+	require.Equal(t, 0, len(proj.Proj.SwiftPackageReferences()))
+	target, ok = proj.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, 0, len(target.SwiftPackageProductDependencies()))
+	require.Equal(t, 0, len(target.BuildPhases()[1].Files))
+
+	content, err = proj.perObjectModify()
+	require.NoError(t, err)
+	for _, id := range []string{packageID, dependencyID, frameworks.Files[0].ID} {
+		require.False(t, strings.Contains(string(content), id))
+	}
+}
+
+const rawSwiftPackages = `
+{
+		4F0A5A3B2A6E6C2E00B6C2A1 /* XCRemoteSwiftPackageReference "Alamofire" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/Alamofire/Alamofire.git";
+			requirement = {
+				kind = upToNextMajorVersion;
+				minimumVersion = 5.8.0;
+			};
+		};
+		4F0A5A3C2A6E6C2E00B6C2A1 /* XCRemoteSwiftPackageReference "firebase-ios-sdk" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/firebase/firebase-ios-sdk";
+			requirement = {
+				kind = versionRange;
+				maximumVersion = 11.0.0;
+				minimumVersion = 10.0.0;
+			};
+		};
+		4F0A5A3D2A6E6C2E00B6C2A1 /* XCLocalSwiftPackageReference "../Packages/Core" */ = {
+			isa = XCLocalSwiftPackageReference;
+			relativePath = ../Packages/Core;
+		};
+		4F0A5A3E2A6E6C2E00B6C2A1 /* Alamofire */ = {
+			isa = XCSwiftPackageProductDependency;
+			package = 4F0A5A3B2A6E6C2E00B6C2A1 /* XCRemoteSwiftPackageReference "Alamofire" */;
+			productName = Alamofire;
+		};
+		4F0A5A3F2A6E6C2E00B6C2A1 /* Core */ = {
+			isa = XCSwiftPackageProductDependency;
+			productName = Core;
+		};
+}
+`
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/xcode-project/serialized"
 )
 
@@ -27,6 +28,19 @@ type Target struct {
 	ProductReference       ProductReference
 	ProductType            string
 	buildPhaseIDs          []string
+	buildPhases            []BuildPhase
+
+	packageProductDependencies []SwiftPackageProductDependency
+}
+
+// SwiftPackageProductDependencies returns the Swift package products the target depends on.
+func (t Target) SwiftPackageProductDependencies() []SwiftPackageProductDependency {
+	return t.packageProductDependencies
+}
+
+// BuildPhases returns the build phases of the target in build order.
+func (t Target) BuildPhases() []BuildPhase {
+	return t.buildPhases
 }
 
 // DependentTargets ...
@@ -168,6 +182,38 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 		return Target{}, err
 	}
 
+	var buildPhases []BuildPhase
+	for _, buildPhaseID := range buildPhaseIDs {
+		buildPhase, err := parseBuildPhase(buildPhaseID, objects)
+		if err != nil {
+			// the build phase object is missing from the project or malformed, reported by Validate
+			if !serialized.IsKeyNotFoundError(err) {
+				log.Warnf("skipping build phase (%s) of target (%s): %s", buildPhaseID, name, err)
+			}
+			continue
+		}
+		buildPhases = append(buildPhases, buildPhase)
+	}
+
+	packageProductDependencyIDs, err := rawTarget.StringSlice("packageProductDependencies")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return Target{}, err
+	}
+
+	var packageProductDependencies []SwiftPackageProductDependency
+	for _, dependencyID := range packageProductDependencyIDs {
+		// dangling package product dependency, reported by Validate
+		if _, err := objects.Object(dependencyID); serialized.IsKeyNotFoundError(err) {
+			continue
+		}
+
+		dependency, err := parseSwiftPackageProductDependency(dependencyID, objects)
+		if err != nil {
+			return Target{}, fmt.Errorf("failed to parse package product dependency (%s) of target (%s): %s", dependencyID, name, err)
+		}
+		packageProductDependencies = append(packageProductDependencies, dependency)
+	}
+
 	return Target{
 		Type:                   targetType,
 		ID:                     id,
@@ -177,5 +223,8 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 		ProductReference:       productReference,
 		ProductType:            productType,
 		buildPhaseIDs:          buildPhaseIDs,
+		buildPhases:            buildPhases,
+
+		packageProductDependencies: packageProductDependencies,
 	}, nil
 }
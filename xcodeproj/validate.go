@@ -0,0 +1,450 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// ValidationSeverity ...
+type ValidationSeverity string
+
+// ValidationSeverities
+const (
+	ErrorValidationSeverity   ValidationSeverity = "error"
+	WarningValidationSeverity ValidationSeverity = "warning"
+)
+
+// ValidationCode identifies the kind of a validation issue, the codes are stable so they can be used to filter the issues.
+type ValidationCode string
+
+// ValidationCodes
+const (
+	// DanglingReferenceValidationCode: an object references an object ID missing from the project (like a target ID in PBXProject.targets without a target object).
+	DanglingReferenceValidationCode ValidationCode = "dangling-reference"
+	// UnreferencedObjectValidationCode: no other object references the object.
+	UnreferencedObjectValidationCode ValidationCode = "unreferenced-object"
+	// DuplicateObjectIDValidationCode: the project.pbxproj contains more than one object with the same ID.
+	DuplicateObjectIDValidationCode ValidationCode = "duplicate-object-id"
+	// BuildFileWithoutFileRefValidationCode: a PBXBuildFile has neither fileRef nor productRef.
+	BuildFileWithoutFileRefValidationCode ValidationCode = "build-file-without-file-ref"
+	// MissingFileValidationCode: a file referenced in the project does not exist on the disk.
+	MissingFileValidationCode ValidationCode = "missing-file"
+	// TargetNotInProjectValidationCode: a target object is missing from PBXProject.targets.
+	TargetNotInProjectValidationCode ValidationCode = "target-not-in-project"
+	// ParseValidationCode: the project can not be opened, like an invalid project.pbxproj property list.
+	ParseValidationCode ValidationCode = "parse-error"
+)
+
+// ValidationIssue is a problem found in the project.
+type ValidationIssue struct {
+	Code     ValidationCode     `json:"code"`
+	Severity ValidationSeverity `json:"severity"`
+	// ObjectID is the ID of the offending object (like the object holding a dangling reference).
+	ObjectID string `json:"object_id"`
+	Message  string `json:"message"`
+}
+
+// String ...
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s): %s", i.Severity, i.Code, i.ObjectID, i.Message)
+}
+
+// referenceListKeys are the object properties holding a list of object IDs.
+var referenceListKeys = []string{
+	"buildConfigurations", "buildPhases", "buildRules", "children", "dependencies", "files",
+	"packageProductDependencies", "packageReferences", "targets",
+}
+
+// referenceKeys are the object properties holding a single object ID.
+var referenceKeys = []string{
+	"baseConfigurationReference", "buildConfigurationList", "containerPortal", "currentVersion", "fileRef", "mainGroup",
+	"package", "productRef", "productRefGroup", "productReference", "remoteRef", "target", "targetProxy",
+}
+
+var targetISAs = map[string]bool{
+	string(NativeTargetType):    true,
+	string(AggregateTargetType): true,
+	string(LegacyTargetType):    true,
+}
+
+// Validate checks the integrity of the project: dangling object references, unreferenced objects, duplicate object IDs,
+// PBXBuildFiles without fileRef, files missing on the disk and targets missing from the PBXProject's targets.
+// The issues are ordered by object ID and code.
+func (p XcodeProj) Validate() ([]ValidationIssue, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := objectIssues(p.Proj.ID, objects, p.originalContents)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Path != "" {
+		issues = append(issues, p.missingFileIssues()...)
+	}
+
+	sortValidationIssues(issues)
+	return issues, nil
+}
+
+// ValidateProject validates the project at the path (see Validate), even if the project can not be opened:
+// the failure is reported as a ParseValidationCode issue, together with the issues of the objects
+// if the project.pbxproj is a valid property list.
+func ValidateProject(pth string) ([]ValidationIssue, error) {
+	proj, openErr := Open(pth)
+	if openErr == nil {
+		return proj.Validate()
+	}
+
+	content, err := fileutil.ReadBytesFromFile(filepath.Join(pth, "project.pbxproj"))
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []ValidationIssue{{
+		Code:     ParseValidationCode,
+		Severity: ErrorValidationSeverity,
+		Message:  openErr.Error(),
+	}}
+
+	var rawPbxProj serialized.Object
+	if _, err := plist.Unmarshal(content, &rawPbxProj); err != nil {
+		return issues, nil
+	}
+	objects, err := rawPbxProj.Object("objects")
+	if err != nil {
+		return issues, nil
+	}
+
+	projectID := ""
+	for id := range objects {
+		if isa, err := objectISA(objects, id); err == nil && isa == "PBXProject" {
+			projectID = id
+			break
+		}
+	}
+
+	if objectIssues, err := objectIssues(projectID, objects, content); err == nil {
+		issues = append(issues, objectIssues...)
+	}
+	sortValidationIssues(issues)
+	return issues, nil
+}
+
+// objectIssues returns the issues of the objects, which do not need the parsed project.
+func objectIssues(projectID string, objects serialized.Object, contents []byte) ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+	issues = append(issues, duplicateObjectIDIssues(contents)...)
+
+	danglingIssues, err := danglingReferenceIssues(projectID, objects)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, danglingIssues...)
+
+	targetIssues, err := targetNotInProjectIssues(projectID, objects)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, targetIssues...)
+
+	notInProject := map[string]bool{}
+	for _, issue := range targetIssues {
+		notInProject[issue.ObjectID] = true
+	}
+	unreferencedIssues, err := unreferencedObjectIssues(projectID, objects, notInProject)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, unreferencedIssues...)
+
+	buildFileIssues, err := buildFileWithoutFileRefIssues(objects)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, buildFileIssues...), nil
+}
+
+func sortValidationIssues(issues []ValidationIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].ObjectID != issues[j].ObjectID {
+			return issues[i].ObjectID < issues[j].ObjectID
+		}
+		return issues[i].Code < issues[j].Code
+	})
+}
+
+var objectEntryPattern = regexp.MustCompile(`(?m)^\t\t([^\s/=]+)(?: /\*.*?\*/)? = \{`)
+
+// duplicateObjectIDIssues looks for duplicate object entries in the project.pbxproj contents,
+// as only the last one of the duplicates is kept when parsing the contents.
+func duplicateObjectIDIssues(contents []byte) []ValidationIssue {
+	counts := map[string]int{}
+	for _, match := range objectEntryPattern.FindAllSubmatch(contents, -1) {
+		counts[strings.Trim(string(match[1]), `"`)]++
+	}
+
+	var issues []ValidationIssue
+	for _, id := range sortedCountKeys(counts) {
+		if counts[id] > 1 {
+			issues = append(issues, ValidationIssue{
+				Code:     DuplicateObjectIDValidationCode,
+				Severity: ErrorValidationSeverity,
+				ObjectID: id,
+				Message:  fmt.Sprintf("object ID is defined %d times", counts[id]),
+			})
+		}
+	}
+	return issues
+}
+
+func sortedCountKeys(counts map[string]int) []string {
+	var keys []string
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func danglingReferenceIssues(projectID string, objects serialized.Object) ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+	addIssue := func(id, keyPath, missingID string) {
+		issues = append(issues, ValidationIssue{
+			Code:     DanglingReferenceValidationCode,
+			Severity: ErrorValidationSeverity,
+			ObjectID: id,
+			Message:  fmt.Sprintf("%s references missing object: %s", keyPath, missingID),
+		})
+	}
+	exists := func(id string) bool {
+		_, ok := objects[id]
+		return ok
+	}
+
+	for _, id := range sortedKeys(objects) {
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range referenceListKeys {
+			ids, err := object.StringSlice(key)
+			if err != nil {
+				continue
+			}
+			for _, referencedID := range ids {
+				if !exists(referencedID) {
+					addIssue(id, key, referencedID)
+				}
+			}
+		}
+
+		for _, key := range referenceKeys {
+			if referencedID, err := object.String(key); err == nil && !exists(referencedID) {
+				addIssue(id, key, referencedID)
+			}
+		}
+
+		// the remote object of a proxy is in the project only if the proxy's container is the project itself
+		if containerPortal, err := object.String("containerPortal"); err == nil && containerPortal == projectID {
+			if referencedID, err := object.String("remoteGlobalIDString"); err == nil && !exists(referencedID) {
+				addIssue(id, "remoteGlobalIDString", referencedID)
+			}
+		}
+
+		if id == projectID {
+			projectReferences, _ := object["projectReferences"].([]interface{})
+			for _, rawProjectReference := range projectReferences {
+				projectReference, ok := rawProjectReference.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, key := range []string{"ProductGroup", "ProjectRef"} {
+					if referencedID, err := serialized.Object(projectReference).String(key); err == nil && !exists(referencedID) {
+						addIssue(id, "projectReferences."+key, referencedID)
+					}
+				}
+			}
+
+			targetAttributes, err := object.Object("attributes")
+			if err == nil {
+				targetAttributes, err = targetAttributes.Object("TargetAttributes")
+			}
+			if err == nil {
+				for _, targetID := range sortedKeys(targetAttributes) {
+					if !exists(targetID) {
+						addIssue(id, "attributes.TargetAttributes", targetID)
+						continue
+					}
+					attributes, err := targetAttributes.Object(targetID)
+					if err != nil {
+						continue
+					}
+					if testTargetID, err := attributes.String("TestTargetID"); err == nil && !exists(testTargetID) {
+						addIssue(id, "attributes.TargetAttributes."+targetID+".TestTargetID", testTargetID)
+					}
+				}
+			}
+		}
+	}
+	return issues, nil
+}
+
+func targetNotInProjectIssues(projectID string, objects serialized.Object) ([]ValidationIssue, error) {
+	project, err := objects.Object(projectID)
+	if err != nil {
+		return nil, err
+	}
+	targetIDs, err := project.StringSlice("targets")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return nil, err
+	}
+	inProject := map[string]bool{}
+	for _, id := range targetIDs {
+		inProject[id] = true
+	}
+
+	var issues []ValidationIssue
+	for _, id := range sortedKeys(objects) {
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, err
+		}
+		isa, err := object.String("isa")
+		if err != nil || !targetISAs[isa] || inProject[id] {
+			continue
+		}
+
+		name, _ := object.String("name")
+		issues = append(issues, ValidationIssue{
+			Code:     TargetNotInProjectValidationCode,
+			Severity: ErrorValidationSeverity,
+			ObjectID: id,
+			Message:  fmt.Sprintf("%s (%s) is missing from the project's targets", isa, name),
+		})
+	}
+	return issues, nil
+}
+
+func unreferencedObjectIssues(projectID string, objects serialized.Object, skip map[string]bool) ([]ValidationIssue, error) {
+	referenced := map[string]bool{}
+	for _, id := range sortedKeys(objects) {
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, err
+		}
+		collectReferencedIDs(map[string]interface{}(object), objects, id, referenced)
+	}
+
+	var issues []ValidationIssue
+	for _, id := range sortedKeys(objects) {
+		if id == projectID || referenced[id] || skip[id] {
+			continue
+		}
+
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, err
+		}
+		isa, _ := object.String("isa")
+		issues = append(issues, ValidationIssue{
+			Code:     UnreferencedObjectValidationCode,
+			Severity: WarningValidationSeverity,
+			ObjectID: id,
+			Message:  fmt.Sprintf("%s is not referenced by any object", isa),
+		})
+	}
+	return issues, nil
+}
+
+// collectReferencedIDs marks the object IDs found in the value (as strings or dictionary keys) as referenced,
+// except for the self references of the object.
+func collectReferencedIDs(value interface{}, objects serialized.Object, selfID string, referenced map[string]bool) {
+	switch v := value.(type) {
+	case serialized.Object:
+		collectReferencedIDs(map[string]interface{}(v), objects, selfID, referenced)
+	case map[string]interface{}:
+		for key, element := range v {
+			if _, ok := objects[key]; ok && key != selfID {
+				referenced[key] = true
+			}
+			collectReferencedIDs(element, objects, selfID, referenced)
+		}
+	case []interface{}:
+		for _, element := range v {
+			collectReferencedIDs(element, objects, selfID, referenced)
+		}
+	case string:
+		if _, ok := objects[v]; ok && v != selfID {
+			referenced[v] = true
+		}
+	}
+}
+
+func buildFileWithoutFileRefIssues(objects serialized.Object) ([]ValidationIssue, error) {
+	var issues []ValidationIssue
+	for _, id := range sortedKeys(objects) {
+		object, err := objects.Object(id)
+		if err != nil {
+			return nil, err
+		}
+		if isa, err := object.String("isa"); err != nil || isa != "PBXBuildFile" {
+			continue
+		}
+
+		_, fileRefErr := object.String("fileRef")
+		_, productRefErr := object.String("productRef")
+		if fileRefErr != nil && productRefErr != nil {
+			issues = append(issues, ValidationIssue{
+				Code:     BuildFileWithoutFileRefValidationCode,
+				Severity: ErrorValidationSeverity,
+				ObjectID: id,
+				Message:  "PBXBuildFile has neither fileRef nor productRef",
+			})
+		}
+	}
+	return issues, nil
+}
+
+// missingFileIssues checks the file references of the project's file tree,
+// the files with a build time only known path (like the products) are skipped.
+// The check is skipped if the file tree can not be parsed (like because of a dangling reference).
+func (p XcodeProj) missingFileIssues() []ValidationIssue {
+	mainGroup, err := p.MainGroup()
+	if err != nil {
+		return nil
+	}
+
+	var issues []ValidationIssue
+	_ = mainGroup.Walk(func(element FileElement) error {
+		if _, ok := element.(*FileReference); !ok {
+			return nil
+		}
+
+		resolved, err := p.ResolveFileElementPath(element, nil)
+		if err != nil || resolved.Symbolic {
+			return nil
+		}
+		if _, err := os.Stat(resolved.Path); os.IsNotExist(err) {
+			issues = append(issues, ValidationIssue{
+				Code:     MissingFileValidationCode,
+				Severity: WarningValidationSeverity,
+				ObjectID: element.Base().ID,
+				Message:  fmt.Sprintf("file does not exist: %s", resolved.Path),
+			})
+		}
+		return nil
+	})
+	return issues
+}
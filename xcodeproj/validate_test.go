@@ -0,0 +1,172 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace func(content string) string
+		want    []ValidationIssue
+	}{
+		{
+			name:    "valid project",
+			replace: func(content string) string { return content },
+			want:    nil,
+		},
+		{
+			name: "build file without fileRef",
+			replace: func(content string) string {
+				return strings.Replace(content, "isa = PBXBuildFile; fileRef = 7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */; ", "isa = PBXBuildFile; ", 1)
+			},
+			want: []ValidationIssue{
+				{Code: BuildFileWithoutFileRefValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D5B360020E28EE80022BAE6", Message: "PBXBuildFile has neither fileRef nor productRef"},
+			},
+		},
+		{
+			name: "dangling references",
+			replace: func(content string) string {
+				return strings.Replace(content, "\t\t7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */ = {isa = PBXFileReference; lastKnownFileType = sourcecode.swift; path = AppDelegate.swift; sourceTree = \"<group>\"; };\n", "", 1)
+			},
+			want: []ValidationIssue{
+				{Code: DanglingReferenceValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D5B35FE20E28EE80022BAE6", Message: "children references missing object: 7D5B35FF20E28EE80022BAE6"},
+				{Code: DanglingReferenceValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D5B360020E28EE80022BAE6", Message: "fileRef references missing object: 7D5B35FF20E28EE80022BAE6"},
+			},
+		},
+		{
+			name: "dangling package references",
+			replace: func(content string) string {
+				content = strings.Replace(content, "\t\t\tmainGroup = 7D5B35F320E28EE80022BAE6;\n", "\t\t\tmainGroup = 7D5B35F320E28EE80022BAE6;\n\t\t\tpackageReferences = (\n\t\t\t\t0000000000000000000000BB,\n\t\t\t);\n", 1)
+				return strings.Replace(content, "\t\t\tproductName = XcodeProj;\n", "\t\t\tpackageProductDependencies = (\n\t\t\t\t0000000000000000000000CC,\n\t\t\t);\n\t\t\tproductName = XcodeProj;\n", 1)
+			},
+			want: []ValidationIssue{
+				{Code: DanglingReferenceValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D5B35F420E28EE80022BAE6", Message: "packageReferences references missing object: 0000000000000000000000BB"},
+				{Code: DanglingReferenceValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D5B35FB20E28EE80022BAE6", Message: "packageProductDependencies references missing object: 0000000000000000000000CC"},
+			},
+		},
+		{
+			name: "duplicate object ID",
+			replace: func(content string) string {
+				line := "\t\t7D5B35FF20E28EE80022BAE6 /* AppDelegate.swift */ = {isa = PBXFileReference; lastKnownFileType = sourcecode.swift; path = AppDelegate.swift; sourceTree = \"<group>\"; };\n"
+				return strings.Replace(content, line, line+line, 1)
+			},
+			want: []ValidationIssue{
+				{Code: DuplicateObjectIDValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D5B35FF20E28EE80022BAE6", Message: "object ID is defined 2 times"},
+			},
+		},
+		{
+			name: "target not in project",
+			replace: func(content string) string {
+				return strings.Replace(content, "\t\t\t\t7D03430C20F4BB070050B6A6 /* TodayExtension */,\n", "", 1)
+			},
+			want: []ValidationIssue{
+				{Code: TargetNotInProjectValidationCode, Severity: ErrorValidationSeverity, ObjectID: "7D03430C20F4BB070050B6A6", Message: "PBXNativeTarget (TodayExtension) is missing from the project's targets"},
+			},
+		},
+		{
+			name: "unreferenced object",
+			replace: func(content string) string {
+				return strings.Replace(content, "/* Begin PBXFileReference section */\n", "/* Begin PBXFileReference section */\n\t\t0000000000000000000000AA /* Orphan.swift */ = {isa = PBXFileReference; lastKnownFileType = sourcecode.swift; path = Orphan.swift; sourceTree = \"<group>\"; };\n", 1)
+			},
+			want: []ValidationIssue{
+				{Code: UnreferencedObjectValidationCode, Severity: WarningValidationSeverity, ObjectID: "0000000000000000000000AA", Message: "PBXFileReference is not referenced by any object"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := tt.replace(testhelper.XcodeProjectTest)
+			require.NotEqual(t, tt.want == nil, content != testhelper.XcodeProjectTest)
+
+			proj, err := parsePBXProjContent([]byte(content))
+			require.NoError(t, err)
+
+			issues, err := proj.Validate()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, issues)
+		})
+	}
+}
+
+func TestXcodeProj_Validate_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	projectPth := filepath.Join(dir, "XcodeProj.xcodeproj")
+	require.NoError(t, os.MkdirAll(projectPth, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(testhelper.XcodeProjectTest), 0644))
+
+	proj, err := Open(projectPth)
+	require.NoError(t, err)
+
+	issues, err := proj.Validate()
+	require.NoError(t, err)
+
+	var missing []string
+	for _, issue := range issues {
+		require.Equal(t, MissingFileValidationCode, issue.Code)
+		require.Equal(t, WarningValidationSeverity, issue.Severity)
+		missing = append(missing, issue.ObjectID)
+	}
+	require.Contains(t, missing, "7D5B35FF20E28EE80022BAE6")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "XcodeProj"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "XcodeProj", "AppDelegate.swift"), nil, 0644))
+
+	issues, err = proj.Validate()
+	require.NoError(t, err)
+	for _, issue := range issues {
+		require.NotEqual(t, "7D5B35FF20E28EE80022BAE6", issue.ObjectID)
+	}
+}
+
+func TestValidateProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ValidationCode
+	}{
+		{
+			name:    "valid project",
+			content: testhelper.XcodeProjectTest,
+			want:    []ValidationCode{MissingFileValidationCode},
+		},
+		{
+			name:    "dangling build configuration list",
+			content: strings.Replace(testhelper.XcodeProjectTest, "buildConfigurationList = 7D5B35F720E28EE80022BAE6", "buildConfigurationList = 0000000000000000000000DD", 1),
+			want:    []ValidationCode{ParseValidationCode, DanglingReferenceValidationCode, UnreferencedObjectValidationCode},
+		},
+		{
+			name:    "invalid property list",
+			content: "// !$*UTF8*$!\n{\n\tobjects = {\n",
+			want:    []ValidationCode{ParseValidationCode},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPth := filepath.Join(t.TempDir(), "XcodeProj.xcodeproj")
+			require.NoError(t, os.MkdirAll(projectPth, 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(tt.content), 0644))
+
+			issues, err := ValidateProject(projectPth)
+			require.NoError(t, err)
+
+			var codes []ValidationCode
+			for _, issue := range issues {
+				if len(codes) == 0 || codes[len(codes)-1] != issue.Code {
+					codes = append(codes, issue.Code)
+				}
+			}
+			require.Equal(t, tt.want, codes)
+		})
+	}
+
+	_, err := ValidateProject(filepath.Join(t.TempDir(), "Missing.xcodeproj"))
+	require.Error(t, err)
+}
@@ -34,6 +34,15 @@ type XcodeProj struct {
 
 	Name string
 	Path string
+
+	// BuildSettingsBackend selects how TargetBuildSettings computes the build settings, defaults to XcodebuildBuildSettingsBackend.
+	BuildSettingsBackend BuildSettingsBackend
+	// IDGenerator generates the IDs of the objects added to the project, defaults to a randomly seeded generator of the project.
+	// Use NewSeededObjectIDGenerator for deterministic IDs.
+	IDGenerator *ObjectIDGenerator
+
+	// schemeChanges are the queued scheme file operations, applied on Save.
+	schemeChanges []schemeChange
 }
 
 func (p XcodeProj) buildSettingsFilePath(target, configuration, key string) (string, error) {
@@ -161,7 +170,7 @@ func (p XcodeProj) TargetBundleID(target, configuration string) (string, error)
 	}
 
 	if bundleID != "" {
-		return Resolve(bundleID, buildSettings)
+		return ExpandBuildSetting(bundleID, buildSettings)
 	}
 
 	informationPropertyList, err := p.TargetInformationPropertyList(target, configuration)
@@ -178,7 +187,7 @@ func (p XcodeProj) TargetBundleID(target, configuration string) (string, error)
 		return "", errors.New("no PRODUCT_BUNDLE_IDENTIFIER build settings nor CFBundleIdentifier information property found")
 	}
 
-	return Resolve(bundleID, buildSettings)
+	return ExpandBuildSetting(bundleID, buildSettings)
 }
 
 // Resolve returns the resolved bundleID. We need this, because the bundleID is not exposed in the .pbxproj file ( raw ).
@@ -187,6 +196,8 @@ func (p XcodeProj) TargetBundleID(target, configuration string) (string, error)
 //**Example:**
 //BundleID in the .pbxproj: Bitrise.Test.$(PRODUCT_NAME:rfc1034identifier).Suffix
 //BundleID after the env is expanded: Bitrise.Test.Sample.Suffix
+//
+// Deprecated: Resolve ignores the build setting operators, use ExpandBuildSetting instead.
 func Resolve(bundleID string, buildSettings serialized.Object) (string, error) {
 	resolvedBundleIDs := map[string]bool{}
 	resolved := bundleID
@@ -278,6 +289,9 @@ func envInBuildSettings(envKey string, buildSettings serialized.Object) (string,
 
 // TargetBuildSettings ...
 func (p XcodeProj) TargetBuildSettings(target, configuration string, customOptions ...string) (serialized.Object, error) {
+	if p.BuildSettingsBackend == OfflineBuildSettingsBackend {
+		return p.OfflineTargetBuildSettings(target, configuration, customOptions...)
+	}
 	return xcodebuild.ShowProjectBuildSettings(p.Path, target, configuration, customOptions...)
 }
 
@@ -383,6 +397,22 @@ func parsePBXProjContent(content []byte) (*XcodeProj, error) {
 	}, nil
 }
 
+// reloadProj parses the Proj again from the RawProj, it needs to be called after modifying the objects of the RawProj.
+func (p *XcodeProj) reloadProj() error {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return err
+	}
+
+	proj, err := parseProj(p.Proj.ID, objects)
+	if err != nil {
+		return fmt.Errorf("failed to parse modified project: %s", err)
+	}
+	p.Proj = proj
+
+	return nil
+}
+
 // IsXcodeProj ...
 func IsXcodeProj(pth string) bool {
 	return filepath.Ext(pth) == ".xcodeproj"
@@ -494,27 +524,42 @@ func writeAttributeForAllSDKs(buildSettings serialized.Object, newKey string, ne
 
 // Save the XcodeProj
 //
-// Overrides the project.pbxproj file of the XcodeProj with the contents of `rawProj`
+// Overrides the project.pbxproj file of the XcodeProj with the contents of `rawProj`,
+// then applies the queued scheme changes (like the removal of the removed target's schemes).
 func (p XcodeProj) Save() error {
-	return p.savePBXProj()
+	if err := p.savePBXProj(); err != nil {
+		return err
+	}
+	return p.saveSchemeChanges()
 }
 
 // savePBXProj overrides the project.pbxproj file of  the XcodeProj with the contents of `rawProj`
 func (p XcodeProj) savePBXProj() error {
 	pth := path.Join(p.Path, "project.pbxproj")
+	newContent, err := p.pbxProjContents()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(pth, newContent, 0644)
+}
+
+// pbxProjContents returns the project.pbxproj contents of `rawProj`,
+// modified in-place if possible, otherwise marshalled from scratch.
+func (p XcodeProj) pbxProjContents() ([]byte, error) {
 	newContent, merr := p.perObjectModify()
 	if merr == nil {
-		return ioutil.WriteFile(pth, newContent, 0644)
+		return newContent, nil
 	}
 	// merr != nil
 	log.Warnf("failed to modify project in-place: %v", merr)
 
-	newContent, err := plist.MarshalIndent(p.RawProj, p.Format, "\t")
+	newContent, err := p.Marshal()
 	if err != nil {
-		return fmt.Errorf("failed to marshal .pbxproj: %v", err)
+		return nil, fmt.Errorf("failed to marshal .pbxproj: %v", err)
 	}
 
-	return ioutil.WriteFile(pth, newContent, 0644)
+	return newContent, nil
 }
 
 const (
@@ -595,6 +640,18 @@ type change struct {
 }
 
 func (p XcodeProj) perObjectModify() ([]byte, error) {
+	// only the objects are replaced in-place
+	for key := range p.RawProj {
+		if key != "objects" && !reflect.DeepEqual(p.RawProj[key], p.originalPbxProj[key]) {
+			return nil, fmt.Errorf("project property changed: %s", key)
+		}
+	}
+	for key := range p.originalPbxProj {
+		if _, ok := p.RawProj[key]; !ok {
+			return nil, fmt.Errorf("project property removed: %s", key)
+		}
+	}
+
 	objectsMod, err := p.RawProj.Object("objects")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse project: %v", err)
@@ -608,7 +665,27 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 		return nil, fmt.Errorf("failed to parse project: %v", err)
 	}
 
-	var mods []change
+	sections, err := findObjectSections(p.originalContents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse project sections: %v", err)
+	}
+
+	mods, removedSections, err := removedObjectChanges(p.originalContents, sections, objectsMod, objectsOrig, objectsAnnotated)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := newPBXProjWriter(p.RawProj, p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	insertions, err := insertedObjectChanges(p.originalContents, sections, removedSections, objectsMod, objectsOrig, objectsAnnotated, w)
+	if err != nil {
+		return nil, err
+	}
+	mods = append(mods, insertions...)
+
 	for keyMod := range objectsMod {
 		objectMod, err := objectsMod.Object(keyMod)
 		if err != nil {
@@ -617,12 +694,8 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 
 		objectOrig, err := objectsOrig.Object(keyMod)
 		if err != nil {
-			return nil, fmt.Errorf("new object added, not in original project: %v", err)
-		}
-
-		objectsAnnotated, err := objectsAnnotated.Object(keyMod)
-		if err != nil {
-			return nil, fmt.Errorf("new object added, not in original annotated project: %v", err)
+			// inserted object
+			continue
 		}
 
 		// If object did not change do nothing
@@ -630,28 +703,15 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 			continue
 		}
 
-		customPosDict, err := objectsAnnotated.Object(customAnnotationKey)
-		if err != nil {
-			return nil, fmt.Errorf("no raw object position available: %v", err)
-		}
-		startPos, err := customPosDict.Int64(startKey)
-		if err != nil {
-			return nil, fmt.Errorf("no raw object start position available: %v", err)
-		}
-		endPos, err := customPosDict.Int64(endKey)
-		if err != nil {
-			return nil, fmt.Errorf("no raw end position availbale: %v", err)
-		}
-
-		contentMod, err := plist.MarshalIndent(objectMod, p.Format, "\t")
+		startPos, endPos, err := objectPosition(objectsAnnotated, keyMod)
 		if err != nil {
-			return nil, fmt.Errorf("could not marshal object (%s): %v", objectsMod, err)
+			return nil, err
 		}
 
 		mods = append(mods, change{
-			start:     int(startPos),
-			end:       int(endPos),
-			rawObject: contentMod,
+			start:     startPos,
+			end:       endPos,
+			rawObject: w.marshalObjectValue(keyMod),
 		})
 	}
 
@@ -659,7 +719,8 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 		return p.originalContents, nil
 	}
 
-	sort.Slice(mods, func(i, j int) bool {
+	// the insertions at the same position keep their order
+	sort.SliceStable(mods, func(i, j int) bool {
 		if mods[i].start == mods[j].start {
 			return mods[i].end < mods[j].end
 		}
@@ -669,7 +730,7 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 	var contentsMod []byte
 	previousEndPos := 0
 	for i, mod := range mods {
-		if i < len(mods)-1 && mod.end >= mods[i+1].start {
+		if i < len(mods)-1 && mod.end > mods[i+1].start {
 			return nil, fmt.Errorf("overlapping changes: %d, %d", mods[i].end, mods[i+1].start)
 		}
 
@@ -682,5 +743,5 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 		contentsMod = append(contentsMod, p.originalContents[previousEndPos:]...)
 	}
 
-	return contentsMod, nil
+	return updateObjectComments(contentsMod, p.originalPbxProj, w)
 }
@@ -813,6 +813,7 @@ func TestXcodeProj_perObjectModify(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			proj, err := parsePBXProjContent([]byte(tt.projContent))
 			require.NoError(t, err)
+			proj.Name = "XcodeProj"
 
 			team := "ABCD1234"
 			signingIdentity := "Apple Development: John Doe (ASDF1234)"
@@ -0,0 +1,218 @@
+package xcscheme
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArgumentsAction is a scheme action, which passes command line arguments and environment variables
+// to the launched app or to the tests.
+type ArgumentsAction string
+
+// ArgumentsActions
+const (
+	LaunchArgumentsAction ArgumentsAction = "launch"
+	TestArgumentsAction   ArgumentsAction = "test"
+)
+
+// SetEnvironmentVariable adds the enabled environment variable to the action, or overrides and enables the existing one.
+func (s *Scheme) SetEnvironmentVariable(action ArgumentsAction, key, value string) error {
+	arguments, variables, err := s.argumentsOf(action)
+	if err != nil {
+		return err
+	}
+
+	updated := append([]EnvironmentVariable{}, variables...)
+	for i, variable := range updated {
+		if variable.Key == key {
+			if variable.Value == value && variable.IsEnabled == "YES" {
+				return nil
+			}
+			updated[i].Value = value
+			updated[i].IsEnabled = "YES"
+			s.setArgumentsOf(action, arguments, updated)
+			return nil
+		}
+	}
+	s.setArgumentsOf(action, arguments, append(updated, EnvironmentVariable{Key: key, Value: value, IsEnabled: "YES"}))
+	return nil
+}
+
+// RemoveEnvironmentVariable removes the environment variable from the action, if it exists.
+func (s *Scheme) RemoveEnvironmentVariable(action ArgumentsAction, key string) error {
+	arguments, variables, err := s.argumentsOf(action)
+	if err != nil {
+		return err
+	}
+
+	var updated []EnvironmentVariable
+	for _, variable := range variables {
+		if variable.Key != key {
+			updated = append(updated, variable)
+		}
+	}
+	if len(updated) != len(variables) {
+		s.setArgumentsOf(action, arguments, updated)
+	}
+	return nil
+}
+
+// SetEnvironmentVariableEnabled toggles the action's existing environment variable.
+func (s *Scheme) SetEnvironmentVariableEnabled(action ArgumentsAction, key string, enabled bool) error {
+	arguments, variables, err := s.argumentsOf(action)
+	if err != nil {
+		return err
+	}
+
+	updated := append([]EnvironmentVariable{}, variables...)
+	for i, variable := range updated {
+		if variable.Key == key {
+			if variable.IsEnabled != yesNo(enabled) {
+				updated[i].IsEnabled = yesNo(enabled)
+				s.setArgumentsOf(action, arguments, updated)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("environment variable not found in %s action: %s", action, key)
+}
+
+// AddCommandLineArgument adds the enabled command line argument to the action, or enables the existing one.
+func (s *Scheme) AddCommandLineArgument(action ArgumentsAction, argument string) error {
+	arguments, variables, err := s.argumentsOf(action)
+	if err != nil {
+		return err
+	}
+
+	updated := append([]CommandLineArgument{}, arguments...)
+	for i, existing := range updated {
+		if existing.Argument == argument {
+			if existing.IsEnabled != "YES" {
+				updated[i].IsEnabled = "YES"
+				s.setArgumentsOf(action, updated, variables)
+			}
+			return nil
+		}
+	}
+	s.setArgumentsOf(action, append(updated, CommandLineArgument{Argument: argument, IsEnabled: "YES"}), variables)
+	return nil
+}
+
+// RemoveCommandLineArgument removes the command line argument from the action, if it exists.
+func (s *Scheme) RemoveCommandLineArgument(action ArgumentsAction, argument string) error {
+	arguments, variables, err := s.argumentsOf(action)
+	if err != nil {
+		return err
+	}
+
+	var updated []CommandLineArgument
+	for _, existing := range arguments {
+		if existing.Argument != argument {
+			updated = append(updated, existing)
+		}
+	}
+	if len(updated) != len(arguments) {
+		s.setArgumentsOf(action, updated, variables)
+	}
+	return nil
+}
+
+// SetCommandLineArgumentEnabled toggles the action's existing command line argument.
+func (s *Scheme) SetCommandLineArgumentEnabled(action ArgumentsAction, argument string, enabled bool) error {
+	arguments, variables, err := s.argumentsOf(action)
+	if err != nil {
+		return err
+	}
+
+	updated := append([]CommandLineArgument{}, arguments...)
+	for i, existing := range updated {
+		if existing.Argument == argument {
+			if existing.IsEnabled != yesNo(enabled) {
+				updated[i].IsEnabled = yesNo(enabled)
+				s.setArgumentsOf(action, updated, variables)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("command line argument not found in %s action: %s", action, argument)
+}
+
+// SetTestActionUsesLaunchArguments sets whether the tests receive the launch action's command line arguments and environment variables.
+// If the test action stops using them, it gets a copy of them: the tests receive the same values until the test action's ones are modified.
+func (s *Scheme) SetTestActionUsesLaunchArguments(use bool) {
+	if use {
+		s.TestAction.ShouldUseLaunchSchemeArgsEnv = "YES"
+		return
+	}
+	if s.TestAction.ShouldUseLaunchSchemeArgsEnv == "YES" {
+		s.setArgumentsOf(TestArgumentsAction, s.LaunchAction.CommandLineArguments, s.LaunchAction.EnvironmentVariables)
+	}
+}
+
+// argumentsOf returns the command line arguments and environment variables the action uses:
+// the launch action's ones for the test action, if the test action uses them.
+func (s Scheme) argumentsOf(action ArgumentsAction) ([]CommandLineArgument, []EnvironmentVariable, error) {
+	switch action {
+	case LaunchArgumentsAction:
+		return s.LaunchAction.CommandLineArguments, s.LaunchAction.EnvironmentVariables, nil
+	case TestArgumentsAction:
+		if s.TestAction.ShouldUseLaunchSchemeArgsEnv == "YES" {
+			return s.LaunchAction.CommandLineArguments, s.LaunchAction.EnvironmentVariables, nil
+		}
+		return s.TestAction.CommandLineArguments, s.TestAction.EnvironmentVariables, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown arguments action: %s", action)
+	}
+}
+
+// setArgumentsOf sets the action's command line arguments and environment variables.
+// The test action stops using the launch action's ones, so the modifications do not affect the launch action.
+func (s *Scheme) setArgumentsOf(action ArgumentsAction, arguments []CommandLineArgument, variables []EnvironmentVariable) {
+	switch action {
+	case LaunchArgumentsAction:
+		s.LaunchAction.CommandLineArguments = arguments
+		s.LaunchAction.EnvironmentVariables = variables
+	case TestArgumentsAction:
+		s.TestAction.CommandLineArguments = append([]CommandLineArgument{}, arguments...)
+		s.TestAction.EnvironmentVariables = append([]EnvironmentVariable{}, variables...)
+		s.TestAction.ShouldUseLaunchSchemeArgsEnv = "NO"
+	}
+}
+
+// SaveTemporaryCopy writes the scheme next to the original scheme with a unique name, like: App-123456.xcscheme,
+// to let xcodebuild use the modified scheme without changing the original one.
+// The caller is responsible for removing the copy's Path.
+func (s Scheme) SaveTemporaryCopy() (Scheme, error) {
+	if s.Path == "" {
+		return Scheme{}, fmt.Errorf("scheme has no path")
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(s.Path), s.Name+"-*.xcscheme")
+	if err != nil {
+		return Scheme{}, err
+	}
+	if err := file.Close(); err != nil {
+		return Scheme{}, err
+	}
+
+	schemeCopy := s
+	schemeCopy.Path = file.Name()
+	schemeCopy.Name = strings.TrimSuffix(filepath.Base(schemeCopy.Path), filepath.Ext(schemeCopy.Path))
+	if err := schemeCopy.Save(); err != nil {
+		if removeErr := os.Remove(schemeCopy.Path); removeErr != nil {
+			return Scheme{}, fmt.Errorf("%s, failed to remove the copy: %s", err, removeErr)
+		}
+		return Scheme{}, err
+	}
+	return schemeCopy, nil
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "YES"
+	}
+	return "NO"
+}
@@ -0,0 +1,125 @@
+package xcscheme
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheme_LaunchArguments(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", fullSchemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	require.NoError(t, scheme.SetEnvironmentVariable(LaunchArgumentsAction, "OS_ACTIVITY_MODE", "default"))
+	require.NoError(t, scheme.SetEnvironmentVariable(LaunchArgumentsAction, "CI", "true"))
+	require.NoError(t, scheme.SetEnvironmentVariableEnabled(LaunchArgumentsAction, "CI", false))
+	require.NoError(t, scheme.AddCommandLineArgument(LaunchArgumentsAction, "-UITestMode"))
+	require.NoError(t, scheme.AddCommandLineArgument(LaunchArgumentsAction, "-UITestMode"))
+	require.NoError(t, scheme.RemoveCommandLineArgument(LaunchArgumentsAction, "-com.apple.CoreData.SQLDebug 1"))
+	require.Error(t, scheme.SetCommandLineArgumentEnabled(LaunchArgumentsAction, "-NotExisting", true))
+	require.Error(t, scheme.SetEnvironmentVariable(ArgumentsAction("archive"), "CI", "true"))
+
+	require.Equal(t, []CommandLineArgument{{Argument: "-UITestMode", IsEnabled: "YES"}}, scheme.LaunchAction.CommandLineArguments)
+	require.Equal(t, []EnvironmentVariable{
+		{Key: "OS_ACTIVITY_MODE", Value: "default", IsEnabled: "YES"},
+		{Key: "CI", Value: "true", IsEnabled: "NO"},
+	}, scheme.LaunchAction.EnvironmentVariables)
+
+	require.NoError(t, scheme.Save())
+	saved, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, scheme.LaunchAction.CommandLineArguments, saved.LaunchAction.CommandLineArguments)
+	require.Equal(t, scheme.LaunchAction.EnvironmentVariables, saved.LaunchAction.EnvironmentVariables)
+}
+
+func TestScheme_TestArguments(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", fullSchemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	t.Log("test action's own arguments")
+	{
+		require.NoError(t, scheme.SetCommandLineArgumentEnabled(TestArgumentsAction, "-Verbose", true))
+		require.NoError(t, scheme.RemoveEnvironmentVariable(TestArgumentsAction, "API_URL"))
+		require.Equal(t, []CommandLineArgument{{Argument: "-UITestMode", IsEnabled: "YES"}, {Argument: "-Verbose", IsEnabled: "YES"}}, scheme.TestCommandLineArguments())
+		require.Equal(t, 0, len(scheme.TestEnvironmentVariables()))
+	}
+
+	t.Log("launch action's arguments, unchanged")
+	{
+		scheme.SetTestActionUsesLaunchArguments(true)
+		require.Equal(t, "YES", scheme.TestAction.ShouldUseLaunchSchemeArgsEnv)
+
+		require.NoError(t, scheme.AddCommandLineArgument(TestArgumentsAction, "-com.apple.CoreData.SQLDebug 1"))
+		require.NoError(t, scheme.SetEnvironmentVariable(TestArgumentsAction, "OS_ACTIVITY_MODE", "disable"))
+		require.NoError(t, scheme.RemoveEnvironmentVariable(TestArgumentsAction, "NOT_EXISTING"))
+		require.Error(t, scheme.SetCommandLineArgumentEnabled(TestArgumentsAction, "-NotExisting", true))
+		require.Equal(t, "YES", scheme.TestAction.ShouldUseLaunchSchemeArgsEnv)
+	}
+
+	t.Log("launch action's arguments")
+	{
+		require.NoError(t, scheme.SetEnvironmentVariable(TestArgumentsAction, "CI", "true"))
+		require.Equal(t, "NO", scheme.TestAction.ShouldUseLaunchSchemeArgsEnv)
+		require.Equal(t, []CommandLineArgument{{Argument: "-com.apple.CoreData.SQLDebug 1", IsEnabled: "YES"}}, scheme.TestCommandLineArguments())
+		require.Equal(t, []EnvironmentVariable{
+			{Key: "OS_ACTIVITY_MODE", Value: "disable", IsEnabled: "YES"},
+			{Key: "CI", Value: "true", IsEnabled: "YES"},
+		}, scheme.TestEnvironmentVariables())
+		require.Equal(t, []EnvironmentVariable{{Key: "OS_ACTIVITY_MODE", Value: "disable", IsEnabled: "YES"}}, scheme.LaunchAction.EnvironmentVariables)
+	}
+}
+
+func TestScheme_SetTestActionUsesLaunchArguments(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", fullSchemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	scheme.SetTestActionUsesLaunchArguments(true)
+	require.Equal(t, scheme.LaunchAction.CommandLineArguments, scheme.TestCommandLineArguments())
+
+	scheme.SetTestActionUsesLaunchArguments(false)
+	require.Equal(t, "NO", scheme.TestAction.ShouldUseLaunchSchemeArgsEnv)
+	require.Equal(t, scheme.LaunchAction.CommandLineArguments, scheme.TestAction.CommandLineArguments)
+	require.Equal(t, scheme.LaunchAction.EnvironmentVariables, scheme.TestAction.EnvironmentVariables)
+
+	require.NoError(t, scheme.AddCommandLineArgument(TestArgumentsAction, "-CI"))
+	require.Equal(t, []CommandLineArgument{{Argument: "-com.apple.CoreData.SQLDebug 1", IsEnabled: "YES"}}, scheme.LaunchAction.CommandLineArguments)
+
+	scheme.SetTestActionUsesLaunchArguments(false)
+	require.Equal(t, 2, len(scheme.TestAction.CommandLineArguments))
+}
+
+func TestScheme_SaveTemporaryCopy(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", fullSchemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	require.NoError(t, scheme.AddCommandLineArgument(TestArgumentsAction, "-CI"))
+	schemeCopy, err := scheme.SaveTemporaryCopy()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Remove(schemeCopy.Path))
+	}()
+
+	require.Equal(t, filepath.Dir(pth), filepath.Dir(schemeCopy.Path))
+	require.True(t, strings.HasPrefix(schemeCopy.Name, "App-"))
+
+	saved, err := Open(schemeCopy.Path)
+	require.NoError(t, err)
+	require.Equal(t, schemeCopy.Name, saved.Name)
+	require.Equal(t, scheme.TestCommandLineArguments(), saved.TestCommandLineArguments())
+
+	content, err := ioutil.ReadFile(pth)
+	require.NoError(t, err)
+	require.Equal(t, fullSchemeContent, string(content))
+
+	_, err = Scheme{}.SaveTemporaryCopy()
+	require.Error(t, err)
+}
@@ -0,0 +1,358 @@
+package xcscheme
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// xmlNode is an element of a scheme's XML document.
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+func (n *xmlNode) isEmpty() bool {
+	return len(n.attrs) == 0 && len(n.children) == 0 && n.text == ""
+}
+
+// parseXMLDocument parses the root element of the XML document, the comments and the processing instructions are dropped.
+func parseXMLDocument(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				node.attrs = append(node.attrs, xml.Attr{Name: xml.Name{Local: attr.Name.Local}, Value: attr.Value})
+			}
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 && strings.TrimSpace(string(t)) != "" {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no root element found")
+	}
+	return root, nil
+}
+
+// Marshal returns the scheme's XML document in Xcode's format (three-space indent, attributes on separate lines).
+// The elements and attributes of the opened scheme file, which are not modelled by the Scheme, are kept
+// and the original order of the elements and attributes is preserved.
+// For a scheme not opened from a file, the empty attributes and elements are omitted,
+// except the containers Xcode always writes (like an empty <Testables>).
+func (s Scheme) Marshal() ([]byte, error) {
+	content, err := xml.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	document, err := parseXMLDocument(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.document != nil {
+		document = mergeXMLNode(s.document, document)
+	} else {
+		document = pruneNewXMLNode(document)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	writeXMLNode(&b, document, 0)
+	return b.Bytes(), nil
+}
+
+// Save writes the scheme to its Path.
+func (s Scheme) Save() error {
+	if s.Path == "" {
+		return fmt.Errorf("scheme has no path")
+	}
+
+	content, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, content, 0644)
+}
+
+// elementSchema lists the attributes and child elements of an element, which are modelled by the Scheme.
+type elementSchema struct {
+	attrs    map[string]bool
+	children map[string]bool
+}
+
+var schemeSchema = newSchemeSchema()
+
+func newSchemeSchema() map[string]*elementSchema {
+	schemas := map[string]*elementSchema{}
+	addElementSchema(schemas, "Scheme", reflect.TypeOf(Scheme{}))
+	return schemas
+}
+
+func elementSchemaOf(schemas map[string]*elementSchema, name string) *elementSchema {
+	schema, ok := schemas[name]
+	if !ok {
+		schema = &elementSchema{attrs: map[string]bool{}, children: map[string]bool{}}
+		schemas[name] = schema
+	}
+	return schema
+}
+
+// addElementSchema collects the attributes and child elements of the element from the xml tags of its type.
+func addElementSchema(schemas map[string]*elementSchema, name string, t reflect.Type) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	_, visited := schemas[name]
+	schema := elementSchemaOf(schemas, name)
+	if visited && len(schema.attrs)+len(schema.children) > 0 {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		fieldName := options[0]
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+
+		isAttr := false
+		for _, option := range options[1:] {
+			if option == "attr" {
+				isAttr = true
+			}
+		}
+		if isAttr {
+			schema.attrs[fieldName] = true
+			continue
+		}
+
+		path := strings.Split(fieldName, ">")
+		parent := schema
+		for j, element := range path {
+			parent.children[element] = true
+			if j < len(path)-1 {
+				parent = elementSchemaOf(schemas, element)
+			}
+		}
+		addElementSchema(schemas, path[len(path)-1], field.Type)
+	}
+}
+
+// mergeXMLNode merges the marshalled element into the original one: the values of the modelled attributes and elements
+// are taken from the marshalled element, the rest of the original element is kept in the original order.
+// The empty elements (like an empty <AdditionalOptions>) are kept only if they exist in the original element,
+// the elements emptied by the modification are removed.
+func mergeXMLNode(original, marshalled *xmlNode) *xmlNode {
+	schema := elementSchemaOf(schemeSchema, original.name)
+	merged := &xmlNode{name: marshalled.name, text: marshalled.text}
+	if merged.text == "" {
+		merged.text = original.text
+	}
+
+	marshalledAttrs := map[string]string{}
+	for _, attr := range marshalled.attrs {
+		marshalledAttrs[attr.Name.Local] = attr.Value
+	}
+	originalAttrs := map[string]bool{}
+	for _, attr := range original.attrs {
+		originalAttrs[attr.Name.Local] = true
+		if value, ok := marshalledAttrs[attr.Name.Local]; ok {
+			merged.attrs = append(merged.attrs, xml.Attr{Name: attr.Name, Value: value})
+		} else if !schema.attrs[attr.Name.Local] || attr.Value == "" {
+			// unknown or empty (omitted) attribute
+			merged.attrs = append(merged.attrs, attr)
+		}
+	}
+	for _, attr := range marshalled.attrs {
+		if !originalAttrs[attr.Name.Local] {
+			merged.attrs = append(merged.attrs, attr)
+		}
+	}
+
+	type child struct {
+		node *xmlNode
+		// position is the position of the original child, or the preceding matched child's for the new children
+		position int
+		isNew    bool
+		index    int
+	}
+	var children []child
+
+	originalByKey := map[string]int{}
+	occurrences := map[string]int{}
+	for i, node := range original.children {
+		originalByKey[fmt.Sprintf("%s/%d", node.name, occurrences[node.name])] = i
+		occurrences[node.name]++
+	}
+
+	matched := map[int]bool{}
+	occurrences = map[string]int{}
+	position := -1
+	for j, node := range marshalled.children {
+		key := fmt.Sprintf("%s/%d", node.name, occurrences[node.name])
+		occurrences[node.name]++
+
+		if i, ok := originalByKey[key]; ok {
+			matched[i] = true
+			position = i
+			if mergedChild := mergeXMLNode(original.children[i], node); !mergedChild.isEmpty() || original.children[i].isEmpty() {
+				children = append(children, child{node: mergedChild, position: i})
+			}
+			continue
+		}
+		if node = pruneXMLNode(node); node == nil {
+			continue
+		}
+		children = append(children, child{node: node, position: position, isNew: true, index: j})
+	}
+
+	for i, node := range original.children {
+		if matched[i] {
+			continue
+		}
+		if !schema.children[node.name] || node.isEmpty() {
+			// unknown or empty element
+			children = append(children, child{node: node, position: i})
+		}
+	}
+
+	sort.SliceStable(children, func(a, b int) bool {
+		if children[a].position != children[b].position {
+			return children[a].position < children[b].position
+		}
+		if children[a].isNew != children[b].isNew {
+			return !children[a].isNew
+		}
+		return children[a].index < children[b].index
+	})
+	for _, c := range children {
+		merged.children = append(merged.children, c.node)
+	}
+
+	return merged
+}
+
+// xcodeEmptyElements are the container elements, which Xcode writes even if they are empty.
+var xcodeEmptyElements = map[string]bool{
+	"BuildActionEntries": true,
+	"Testables":          true,
+}
+
+// pruneNewXMLNode returns the element of a new scheme without its empty attributes (like an unset buildConfiguration)
+// and without its blank descendants, except the containers listed in xcodeEmptyElements.
+func pruneNewXMLNode(node *xmlNode) *xmlNode {
+	pruned := &xmlNode{name: node.name, text: node.text}
+	for _, attr := range node.attrs {
+		if attr.Value != "" {
+			pruned.attrs = append(pruned.attrs, attr)
+		}
+	}
+	for _, child := range node.children {
+		prunedChild := pruneNewXMLNode(child)
+		if prunedChild.isEmpty() && !xcodeEmptyElements[child.name] {
+			continue
+		}
+		pruned.children = append(pruned.children, prunedChild)
+	}
+	return pruned
+}
+
+// pruneXMLNode returns the element without its blank descendants, or nil if the element itself is blank:
+// it has no text, no attribute with value and no child which is not blank (like the marshalled empty <PreActions>).
+func pruneXMLNode(node *xmlNode) *xmlNode {
+	pruned := &xmlNode{name: node.name, attrs: node.attrs, text: node.text}
+	for _, child := range node.children {
+		if prunedChild := pruneXMLNode(child); prunedChild != nil {
+			pruned.children = append(pruned.children, prunedChild)
+		}
+	}
+	if pruned.text != "" || len(pruned.children) > 0 {
+		return pruned
+	}
+	for _, attr := range pruned.attrs {
+		if attr.Value != "" {
+			return pruned
+		}
+	}
+	return nil
+}
+
+var xmlAttributeEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+	"\n", "&#10;",
+	"\r", "&#13;",
+	"\t", "&#9;",
+)
+
+func writeXMLNode(b *bytes.Buffer, node *xmlNode, depth int) {
+	indent := strings.Repeat("   ", depth)
+
+	b.WriteString(indent + "<" + node.name)
+	for _, attr := range node.attrs {
+		b.WriteString(fmt.Sprintf("\n%s   %s = \"%s\"", indent, attr.Name.Local, xmlAttributeEscaper.Replace(attr.Value)))
+	}
+	b.WriteString(">")
+
+	if node.text != "" {
+		if err := xml.EscapeText(b, []byte(node.text)); err != nil {
+			b.WriteString(node.text)
+		}
+		b.WriteString("</" + node.name + ">\n")
+		return
+	}
+
+	b.WriteString("\n")
+	for _, child := range node.children {
+		writeXMLNode(b, child, depth+1)
+	}
+	b.WriteString(indent + "</" + node.name + ">\n")
+}
@@ -0,0 +1,146 @@
+package xcscheme
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheme_Marshal_RoundTrip(t *testing.T) {
+	for _, content := range []string{schemeContent, fullSchemeContent} {
+		pth := testhelper.CreateTmpFile(t, "App.xcscheme", content)
+		scheme, err := Open(pth)
+		require.NoError(t, err)
+
+		marshalled, err := scheme.Marshal()
+		require.NoError(t, err)
+		require.Equal(t, content, string(marshalled))
+	}
+}
+
+func TestScheme_Marshal_KeepsUnknownNodes(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", unknownNodesSchemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	scheme.LaunchAction.BuildConfiguration = "Release"
+	scheme.LaunchAction.CommandLineArguments = nil
+	scheme.LaunchAction.EnvironmentVariables = append(scheme.LaunchAction.EnvironmentVariables, EnvironmentVariable{Key: "GREETING", Value: `it's "quoted"`, IsEnabled: "YES"})
+
+	marshalled, err := scheme.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1230"
+   version = "1.3">
+   <LaunchAction
+      customAttribute = "kept"
+      buildConfiguration = "Release"
+      launchStyle = "0">
+      <CustomElement
+         value = "kept">
+         <Nested>
+         </Nested>
+      </CustomElement>
+      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "OS_ACTIVITY_MODE"
+            value = "disable"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+         <EnvironmentVariable
+            key = "GREETING"
+            value = "it&apos;s &quot;quoted&quot;"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+      <AdditionalOptions>
+      </AdditionalOptions>
+   </LaunchAction>
+</Scheme>
+`, string(marshalled))
+}
+
+func TestScheme_Marshal_NewScheme(t *testing.T) {
+	scheme := Scheme{LastUpgradeVersion: "1000", Version: "1.3"}
+	scheme.BuildAction.ParallelizeBuildables = "YES"
+	scheme.TestAction.BuildConfiguration = "Debug"
+	scheme.LaunchAction.BuildConfiguration = "Debug"
+
+	marshalled, err := scheme.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1000"
+   version = "1.3">
+   <BuildAction
+      parallelizeBuildables = "YES">
+      <BuildActionEntries>
+      </BuildActionEntries>
+   </BuildAction>
+   <TestAction
+      buildConfiguration = "Debug">
+      <Testables>
+      </Testables>
+   </TestAction>
+   <LaunchAction
+      buildConfiguration = "Debug">
+   </LaunchAction>
+</Scheme>
+`, string(marshalled))
+}
+
+func TestScheme_Save(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", schemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	scheme.ArchiveAction.BuildConfiguration = "Debug"
+	require.NoError(t, scheme.Save())
+
+	saved, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, "Debug", saved.ArchiveAction.BuildConfiguration)
+
+	content, err := ioutil.ReadFile(pth)
+	require.NoError(t, err)
+	marshalled, err := saved.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, string(content), string(marshalled))
+
+	require.Error(t, Scheme{}.Save())
+}
+
+const unknownNodesSchemeContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1230"
+   version = "1.3">
+   <LaunchAction
+      customAttribute = "kept"
+      buildConfiguration = "Debug"
+      launchStyle = "0">
+      <CustomElement
+         value = "kept">
+         <Nested>
+         </Nested>
+      </CustomElement>
+      <CommandLineArguments>
+         <CommandLineArgument
+            argument = "-Verbose"
+            isEnabled = "YES">
+         </CommandLineArgument>
+      </CommandLineArguments>
+      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "OS_ACTIVITY_MODE"
+            value = "disable"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+      <AdditionalOptions>
+      </AdditionalOptions>
+   </LaunchAction>
+</Scheme>
+`
@@ -0,0 +1,150 @@
+package xcscheme
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const (
+	schemeManagementFileName = "xcschememanagement.plist"
+	// sharedSchemeKeySuffix marks the shared schemes' keys in the xcschememanagement.plist.
+	sharedSchemeKeySuffix = "_^#shared#^_"
+)
+
+// schemeOwner returns whether the scheme at the path is shared and the owner of the user scheme,
+// based on the scheme's directory: xcshareddata/xcschemes or xcuserdata/<User>.xcuserdatad/xcschemes.
+func schemeOwner(pth string) (bool, string) {
+	schemesDir := filepath.Dir(pth)
+	if filepath.Base(schemesDir) != "xcschemes" {
+		return false, ""
+	}
+
+	dataDir := filepath.Dir(schemesDir)
+	if filepath.Base(dataDir) == "xcshareddata" {
+		return true, ""
+	}
+	if filepath.Ext(dataDir) == ".xcuserdatad" && filepath.Base(filepath.Dir(dataDir)) == "xcuserdata" {
+		return false, strings.TrimSuffix(filepath.Base(dataDir), ".xcuserdatad")
+	}
+	return false, ""
+}
+
+// Share moves the user scheme to the shared schemes of its container (project or workspace),
+// and updates the scheme's entry in the user's xcschememanagement.plist.
+// If the container already has a shared scheme with the same name, the scheme gets a numbered name, like: "App 2".
+func (s *Scheme) Share() error {
+	if s.IsShared {
+		return nil
+	}
+	if s.User == "" {
+		return fmt.Errorf("scheme is not a user scheme: %s", s.Path)
+	}
+
+	userSchemesDir := filepath.Dir(s.Path)
+	containerDir := filepath.Dir(filepath.Dir(filepath.Dir(userSchemesDir)))
+	sharedSchemesDir := filepath.Join(containerDir, "xcshareddata", "xcschemes")
+	if err := os.MkdirAll(sharedSchemesDir, 0755); err != nil {
+		return err
+	}
+
+	name := s.Name
+	for i := 2; ; i++ {
+		exist, err := pathutil.IsPathExists(filepath.Join(sharedSchemesDir, name+".xcscheme"))
+		if err != nil {
+			return err
+		}
+		if !exist {
+			break
+		}
+		name = fmt.Sprintf("%s %d", s.Name, i)
+	}
+
+	content, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+
+	managementPth := filepath.Join(userSchemesDir, schemeManagementFileName)
+	originalManagement, err := ioutil.ReadFile(managementPth)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	sharedPth := filepath.Join(sharedSchemesDir, name+".xcscheme")
+	if err := ioutil.WriteFile(sharedPth, content, 0644); err != nil {
+		return err
+	}
+
+	// the user scheme is removed last, the previous steps are rolled back if a later one fails
+	if err := shareSchemeManagementEntry(managementPth, s.Name, name); err != nil {
+		return rollbackShare(fmt.Errorf("failed to update %s: %s", schemeManagementFileName, err), sharedPth, "", nil)
+	}
+	if err := os.Remove(s.Path); err != nil {
+		return rollbackShare(err, sharedPth, managementPth, originalManagement)
+	}
+
+	s.Name = name
+	s.Path = sharedPth
+	s.IsShared = true
+	s.User = ""
+
+	return nil
+}
+
+// rollbackShare removes the shared copy of the scheme and restores the original xcschememanagement.plist (if managementPth is set),
+// it returns the error of the failed step, extended with the errors of the rollback.
+func rollbackShare(err error, sharedPth, managementPth string, originalManagement []byte) error {
+	if removeErr := os.Remove(sharedPth); removeErr != nil {
+		err = fmt.Errorf("%s, failed to remove the shared scheme: %s", err, removeErr)
+	}
+	if managementPth != "" && originalManagement != nil {
+		if writeErr := ioutil.WriteFile(managementPth, originalManagement, 0644); writeErr != nil {
+			err = fmt.Errorf("%s, failed to restore %s: %s", err, schemeManagementFileName, writeErr)
+		}
+	}
+	return err
+}
+
+// shareSchemeManagementEntry renames the user scheme's key to the shared scheme's key in the xcschememanagement.plist,
+// to keep the scheme's order and visibility in Xcode.
+func shareSchemeManagementEntry(pth, userSchemeName, sharedSchemeName string) error {
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		return err
+	} else if !exist {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(pth)
+	if err != nil {
+		return err
+	}
+	var management map[string]interface{}
+	format, err := plist.Unmarshal(content, &management)
+	if err != nil {
+		return err
+	}
+
+	userState, ok := management["SchemeUserState"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	userKey := userSchemeName + ".xcscheme"
+	state, ok := userState[userKey]
+	if !ok {
+		return nil
+	}
+	delete(userState, userKey)
+	userState[sharedSchemeName+".xcscheme"+sharedSchemeKeySuffix] = state
+
+	content, err = plist.MarshalIndent(management, format, "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(pth, content, 0644)
+}
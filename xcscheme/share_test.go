@@ -0,0 +1,134 @@
+package xcscheme
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheme_Share(t *testing.T) {
+	projectPth := filepath.Join(t.TempDir(), "App.xcodeproj")
+	sharedSchemesDir := filepath.Join(projectPth, "xcshareddata", "xcschemes")
+	userSchemesDir := filepath.Join(projectPth, "xcuserdata", "john.xcuserdatad", "xcschemes")
+	require.NoError(t, os.MkdirAll(sharedSchemesDir, 0755))
+	require.NoError(t, os.MkdirAll(userSchemesDir, 0755))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sharedSchemesDir, "App.xcscheme"), []byte(schemeContent), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(userSchemesDir, "App.xcscheme"), []byte(schemeContent), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(userSchemesDir, "Tests.xcscheme"), []byte(schemeContent), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(userSchemesDir, schemeManagementFileName), []byte(schemeManagementContent), 0644))
+
+	schemes, err := FindSchemesIn(projectPth)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(schemes))
+	require.True(t, schemes[0].IsShared)
+	require.Equal(t, "", schemes[0].User)
+	require.False(t, schemes[1].IsShared)
+	require.Equal(t, "john", schemes[1].User)
+
+	t.Log("name collision")
+	{
+		scheme := schemes[1]
+		require.Equal(t, "App", scheme.Name)
+		require.NoError(t, scheme.Share())
+		require.Equal(t, "App 2", scheme.Name)
+		require.Equal(t, filepath.Join(sharedSchemesDir, "App 2.xcscheme"), scheme.Path)
+		require.True(t, scheme.IsShared)
+		require.Equal(t, "", scheme.User)
+
+		exist, err := pathutil.IsPathExists(filepath.Join(userSchemesDir, "App.xcscheme"))
+		require.NoError(t, err)
+		require.False(t, exist)
+
+		content, err := ioutil.ReadFile(scheme.Path)
+		require.NoError(t, err)
+		require.Equal(t, schemeContent, string(content))
+	}
+
+	t.Log("shared scheme")
+	{
+		scheme := schemes[0]
+		require.NoError(t, scheme.Share())
+		require.Equal(t, filepath.Join(sharedSchemesDir, "App.xcscheme"), scheme.Path)
+	}
+
+	scheme := schemes[2]
+	require.NoError(t, scheme.Share())
+	require.Equal(t, "Tests", scheme.Name)
+
+	content, err := ioutil.ReadFile(filepath.Join(userSchemesDir, schemeManagementFileName))
+	require.NoError(t, err)
+	var management map[string]interface{}
+	_, err = plist.Unmarshal(content, &management)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"App.xcscheme_^#shared#^_":   map[string]interface{}{"orderHint": uint64(0)},
+		"App 2.xcscheme_^#shared#^_": map[string]interface{}{"orderHint": uint64(1)},
+		"Tests.xcscheme_^#shared#^_": map[string]interface{}{"orderHint": uint64(2)},
+	}, management["SchemeUserState"])
+
+	schemes, err = FindSchemesIn(projectPth)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(schemes))
+	for _, scheme := range schemes {
+		require.True(t, scheme.IsShared)
+	}
+}
+
+const schemeManagementContent = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>SchemeUserState</key>
+	<dict>
+		<key>App.xcscheme_^#shared#^_</key>
+		<dict>
+			<key>orderHint</key>
+			<integer>0</integer>
+		</dict>
+		<key>App.xcscheme</key>
+		<dict>
+			<key>orderHint</key>
+			<integer>1</integer>
+		</dict>
+		<key>Tests.xcscheme</key>
+		<dict>
+			<key>orderHint</key>
+			<integer>2</integer>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestScheme_Share_Rollback(t *testing.T) {
+	projectPth := filepath.Join(t.TempDir(), "App.xcodeproj")
+	sharedSchemesDir := filepath.Join(projectPth, "xcshareddata", "xcschemes")
+	userSchemesDir := filepath.Join(projectPth, "xcuserdata", "john.xcuserdatad", "xcschemes")
+	require.NoError(t, os.MkdirAll(userSchemesDir, 0755))
+
+	userPth := filepath.Join(userSchemesDir, "App.xcscheme")
+	require.NoError(t, ioutil.WriteFile(userPth, []byte(schemeContent), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(userSchemesDir, schemeManagementFileName), []byte("<plist>"), 0644))
+
+	scheme, err := Open(userPth)
+	require.NoError(t, err)
+	require.Error(t, scheme.Share())
+
+	require.Equal(t, "App", scheme.Name)
+	require.Equal(t, userPth, scheme.Path)
+	require.False(t, scheme.IsShared)
+	require.Equal(t, "john", scheme.User)
+
+	exist, err := pathutil.IsPathExists(userPth)
+	require.NoError(t, err)
+	require.True(t, exist)
+	exist, err = pathutil.IsPathExists(filepath.Join(sharedSchemesDir, "App.xcscheme"))
+	require.NoError(t, err)
+	require.False(t, exist)
+}
@@ -12,9 +12,10 @@ import (
 
 // BuildableReference ...
 type BuildableReference struct {
+	BuildableIdentifier string `xml:"BuildableIdentifier,attr,omitempty"`
 	BlueprintIdentifier string `xml:"BlueprintIdentifier,attr"`
-	BlueprintName       string `xml:"BlueprintName,attr"`
 	BuildableName       string `xml:"BuildableName,attr"`
+	BlueprintName       string `xml:"BlueprintName,attr"`
 	ReferencedContainer string `xml:"ReferencedContainer,attr"`
 }
 
@@ -35,43 +36,247 @@ func (r BuildableReference) ReferencedContainerAbsPath(schemeContainerDir string
 	return pathutil.AbsPath(absPth)
 }
 
+// ExecutionActionTypes
+const (
+	ShellScriptExecutionActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.ShellScriptAction"
+	SendEmailExecutionActionType   = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.SendEmailAction"
+)
+
+// ExecutionAction is a pre- or post-action of a scheme action: a run script or a send email action.
+type ExecutionAction struct {
+	ActionType    string `xml:"ActionType,attr"`
+	ActionContent ActionContent
+}
+
+// ActionContent ...
+type ActionContent struct {
+	Title string `xml:"title,attr,omitempty"`
+
+	// ShellScriptAction properties
+	ScriptText     string `xml:"scriptText,attr,omitempty"`
+	ShellToExecute string `xml:"shellToExecute,attr,omitempty"`
+	// EnvironmentBuildable is the target the build settings of the script's environment are taken from.
+	EnvironmentBuildable *BuildableReference `xml:"EnvironmentBuildable>BuildableReference"`
+
+	// SendEmailAction properties
+	EmailRecipient   string `xml:"emailRecipient,attr,omitempty"`
+	EmailSubject     string `xml:"emailSubject,attr,omitempty"`
+	EmailBody        string `xml:"emailBody,attr,omitempty"`
+	AttachLogToEmail string `xml:"attachLogToEmail,attr,omitempty"`
+}
+
+// CommandLineArgument ...
+type CommandLineArgument struct {
+	Argument  string `xml:"argument,attr"`
+	IsEnabled string `xml:"isEnabled,attr"`
+}
+
+// EnvironmentVariable ...
+type EnvironmentVariable struct {
+	Key       string `xml:"key,attr"`
+	Value     string `xml:"value,attr"`
+	IsEnabled string `xml:"isEnabled,attr"`
+}
+
+// AdditionalOption is a diagnostics option, like: MallocStackLogging or NSZombieEnabled.
+type AdditionalOption struct {
+	Key       string `xml:"key,attr"`
+	Value     string `xml:"value,attr"`
+	IsEnabled string `xml:"isEnabled,attr"`
+}
+
+// BuildableProductRunnable is the product run by the launch and profile actions.
+type BuildableProductRunnable struct {
+	RunnableDebuggingMode string `xml:"runnableDebuggingMode,attr,omitempty"`
+	BuildableReference    BuildableReference
+}
+
+// RemoteRunnable is the app run on a remote device by the launch and profile actions, like a watchOS app.
+type RemoteRunnable struct {
+	RunnableDebuggingMode string `xml:"runnableDebuggingMode,attr,omitempty"`
+	BundleIdentifier      string `xml:"BundleIdentifier,attr,omitempty"`
+	RemotePath            string `xml:"RemotePath,attr,omitempty"`
+	BuildableReference    BuildableReference
+}
+
+// MacroExpansion is the target the build settings in the arguments and environment variables are expanded with.
+type MacroExpansion struct {
+	BuildableReference BuildableReference
+}
+
+// LocationScenarioReference is the location simulated by the launch action.
+type LocationScenarioReference struct {
+	Identifier    string `xml:"identifier,attr"`
+	ReferenceType string `xml:"referenceType,attr"`
+}
+
+// StoreKitConfigurationFileReference ...
+type StoreKitConfigurationFileReference struct {
+	Identifier string `xml:"identifier,attr"`
+}
+
 // BuildActionEntry ...
 type BuildActionEntry struct {
 	BuildForTesting    string `xml:"buildForTesting,attr"`
+	BuildForRunning    string `xml:"buildForRunning,attr,omitempty"`
+	BuildForProfiling  string `xml:"buildForProfiling,attr,omitempty"`
 	BuildForArchiving  string `xml:"buildForArchiving,attr"`
+	BuildForAnalyzing  string `xml:"buildForAnalyzing,attr,omitempty"`
 	BuildableReference BuildableReference
 }
 
 // BuildAction ...
 type BuildAction struct {
+	ParallelizeBuildables     string `xml:"parallelizeBuildables,attr,omitempty"`
+	BuildImplicitDependencies string `xml:"buildImplicitDependencies,attr,omitempty"`
+	RunPostActionsOnFailure   string `xml:"runPostActionsOnFailure,attr,omitempty"`
+
+	PreActions         []ExecutionAction  `xml:"PreActions>ExecutionAction"`
+	PostActions        []ExecutionAction  `xml:"PostActions>ExecutionAction"`
 	BuildActionEntries []BuildActionEntry `xml:"BuildActionEntries>BuildActionEntry"`
 }
 
+// Test is a test class or method, like: AppTests/testExample().
+type Test struct {
+	Identifier string `xml:"Identifier,attr"`
+}
+
 // TestableReference ...
 type TestableReference struct {
-	Skipped            string `xml:"skipped,attr"`
-	BuildableReference BuildableReference
+	Skipped                   string `xml:"skipped,attr"`
+	Parallelizable            string `xml:"parallelizable,attr,omitempty"`
+	TestExecutionOrdering     string `xml:"testExecutionOrdering,attr,omitempty"`
+	UseTestSelectionWhitelist string `xml:"useTestSelectionWhitelist,attr,omitempty"`
+	BuildableReference        BuildableReference
+	SkippedTests              []Test `xml:"SkippedTests>Test"`
+	SelectedTests             []Test `xml:"SelectedTests>Test"`
+}
+
+// TestPlanReference ...
+type TestPlanReference struct {
+	Reference string `xml:"reference,attr"`
+	Default   string `xml:"default,attr,omitempty"`
 }
 
 // TestAction ...
 type TestAction struct {
-	Testables          []TestableReference `xml:"Testables>TestableReference"`
-	BuildConfiguration string              `xml:"buildConfiguration,attr"`
+	BuildConfiguration         string `xml:"buildConfiguration,attr"`
+	SelectedDebuggerIdentifier string `xml:"selectedDebuggerIdentifier,attr,omitempty"`
+	SelectedLauncherIdentifier string `xml:"selectedLauncherIdentifier,attr,omitempty"`
+	Language                   string `xml:"language,attr,omitempty"`
+	Region                     string `xml:"region,attr,omitempty"`
+	// ShouldUseLaunchSchemeArgsEnv is YES if the tests receive the launch action's arguments and environment variables.
+	ShouldUseLaunchSchemeArgsEnv            string `xml:"shouldUseLaunchSchemeArgsEnv,attr,omitempty"`
+	CodeCoverageEnabled                     string `xml:"codeCoverageEnabled,attr,omitempty"`
+	OnlyGenerateCoverageForSpecifiedTargets string `xml:"onlyGenerateCoverageForSpecifiedTargets,attr,omitempty"`
+	EnableAddressSanitizer                  string `xml:"enableAddressSanitizer,attr,omitempty"`
+	EnableThreadSanitizer                   string `xml:"enableThreadSanitizer,attr,omitempty"`
+	EnableUBSanitizer                       string `xml:"enableUBSanitizer,attr,omitempty"`
+	DisableMainThreadChecker                string `xml:"disableMainThreadChecker,attr,omitempty"`
+
+	PreActions           []ExecutionAction   `xml:"PreActions>ExecutionAction"`
+	PostActions          []ExecutionAction   `xml:"PostActions>ExecutionAction"`
+	TestPlans            []TestPlanReference `xml:"TestPlans>TestPlanReference"`
+	Testables            []TestableReference `xml:"Testables>TestableReference"`
+	MacroExpansion       *MacroExpansion
+	CommandLineArguments []CommandLineArgument `xml:"CommandLineArguments>CommandLineArgument"`
+	EnvironmentVariables []EnvironmentVariable `xml:"EnvironmentVariables>EnvironmentVariable"`
+	AdditionalOptions    []AdditionalOption    `xml:"AdditionalOptions>AdditionalOption"`
+	CodeCoverageTargets  []BuildableReference  `xml:"CodeCoverageTargets>BuildableReference"`
+}
+
+// LaunchAction is the Run action of the scheme.
+type LaunchAction struct {
+	BuildConfiguration                string `xml:"buildConfiguration,attr,omitempty"`
+	SelectedDebuggerIdentifier        string `xml:"selectedDebuggerIdentifier,attr,omitempty"`
+	SelectedLauncherIdentifier        string `xml:"selectedLauncherIdentifier,attr,omitempty"`
+	Language                          string `xml:"language,attr,omitempty"`
+	Region                            string `xml:"region,attr,omitempty"`
+	LaunchStyle                       string `xml:"launchStyle,attr,omitempty"`
+	UseCustomWorkingDirectory         string `xml:"useCustomWorkingDirectory,attr,omitempty"`
+	CustomWorkingDirectory            string `xml:"customWorkingDirectory,attr,omitempty"`
+	IgnoresPersistentStateOnLaunch    string `xml:"ignoresPersistentStateOnLaunch,attr,omitempty"`
+	DebugDocumentVersioning           string `xml:"debugDocumentVersioning,attr,omitempty"`
+	DebugServiceExtension             string `xml:"debugServiceExtension,attr,omitempty"`
+	AllowLocationSimulation           string `xml:"allowLocationSimulation,attr,omitempty"`
+	EnableAddressSanitizer            string `xml:"enableAddressSanitizer,attr,omitempty"`
+	EnableThreadSanitizer             string `xml:"enableThreadSanitizer,attr,omitempty"`
+	EnableUBSanitizer                 string `xml:"enableUBSanitizer,attr,omitempty"`
+	DisableMainThreadChecker          string `xml:"disableMainThreadChecker,attr,omitempty"`
+	StopOnEveryMainThreadCheckerIssue string `xml:"stopOnEveryMainThreadCheckerIssue,attr,omitempty"`
+
+	PreActions                         []ExecutionAction `xml:"PreActions>ExecutionAction"`
+	PostActions                        []ExecutionAction `xml:"PostActions>ExecutionAction"`
+	BuildableProductRunnable           *BuildableProductRunnable
+	RemoteRunnable                     *RemoteRunnable
+	MacroExpansion                     *MacroExpansion
+	CommandLineArguments               []CommandLineArgument `xml:"CommandLineArguments>CommandLineArgument"`
+	EnvironmentVariables               []EnvironmentVariable `xml:"EnvironmentVariables>EnvironmentVariable"`
+	LocationScenarioReference          *LocationScenarioReference
+	AdditionalOptions                  []AdditionalOption `xml:"AdditionalOptions>AdditionalOption"`
+	StoreKitConfigurationFileReference *StoreKitConfigurationFileReference
+}
+
+// ProfileAction ...
+type ProfileAction struct {
+	BuildConfiguration string `xml:"buildConfiguration,attr,omitempty"`
+	// ShouldUseLaunchSchemeArgsEnv is YES if the profiled app receives the launch action's arguments and environment variables.
+	ShouldUseLaunchSchemeArgsEnv   string `xml:"shouldUseLaunchSchemeArgsEnv,attr,omitempty"`
+	SavedToolIdentifier            string `xml:"savedToolIdentifier,attr,omitempty"`
+	UseCustomWorkingDirectory      string `xml:"useCustomWorkingDirectory,attr,omitempty"`
+	CustomWorkingDirectory         string `xml:"customWorkingDirectory,attr,omitempty"`
+	IgnoresPersistentStateOnLaunch string `xml:"ignoresPersistentStateOnLaunch,attr,omitempty"`
+	DebugDocumentVersioning        string `xml:"debugDocumentVersioning,attr,omitempty"`
+
+	PreActions               []ExecutionAction `xml:"PreActions>ExecutionAction"`
+	PostActions              []ExecutionAction `xml:"PostActions>ExecutionAction"`
+	BuildableProductRunnable *BuildableProductRunnable
+	RemoteRunnable           *RemoteRunnable
+	MacroExpansion           *MacroExpansion
+	CommandLineArguments     []CommandLineArgument `xml:"CommandLineArguments>CommandLineArgument"`
+	EnvironmentVariables     []EnvironmentVariable `xml:"EnvironmentVariables>EnvironmentVariable"`
+}
+
+// AnalyzeAction ...
+type AnalyzeAction struct {
+	BuildConfiguration string `xml:"buildConfiguration,attr,omitempty"`
+
+	PreActions  []ExecutionAction `xml:"PreActions>ExecutionAction"`
+	PostActions []ExecutionAction `xml:"PostActions>ExecutionAction"`
 }
 
 // ArchiveAction ...
 type ArchiveAction struct {
-	BuildConfiguration string `xml:"buildConfiguration,attr"`
+	BuildConfiguration       string `xml:"buildConfiguration,attr"`
+	CustomArchiveName        string `xml:"customArchiveName,attr,omitempty"`
+	RevealArchiveInOrganizer string `xml:"revealArchiveInOrganizer,attr,omitempty"`
+
+	PreActions  []ExecutionAction `xml:"PreActions>ExecutionAction"`
+	PostActions []ExecutionAction `xml:"PostActions>ExecutionAction"`
 }
 
 // Scheme ...
 type Scheme struct {
+	LastUpgradeVersion        string `xml:"LastUpgradeVersion,attr,omitempty"`
+	Version                   string `xml:"version,attr,omitempty"`
+	WasCreatedForAppExtension string `xml:"wasCreatedForAppExtension,attr,omitempty"`
+
 	BuildAction   BuildAction
-	ArchiveAction ArchiveAction
 	TestAction    TestAction
+	LaunchAction  LaunchAction
+	ProfileAction ProfileAction
+	AnalyzeAction AnalyzeAction
+	ArchiveAction ArchiveAction
 
-	Name string
-	Path string
+	Name string `xml:"-"`
+	Path string `xml:"-"`
+	// IsShared is true if the scheme is in the container's xcshareddata directory.
+	IsShared bool `xml:"-"`
+	// User is the owner of the user scheme (the scheme in the container's xcuserdata/<User>.xcuserdatad directory).
+	User string `xml:"-"`
+
+	// document is the parsed scheme file, Marshal keeps its unmodelled elements and attributes.
+	document *xmlNode
 }
 
 // Open ...
@@ -86,12 +291,65 @@ func Open(pth string) (Scheme, error) {
 		return Scheme{}, fmt.Errorf("failed to unmarshal scheme file: %s, error: %s", pth, err)
 	}
 
+	document, err := parseXMLDocument(b)
+	if err != nil {
+		return Scheme{}, fmt.Errorf("failed to parse scheme file: %s, error: %s", pth, err)
+	}
+	scheme.document = document
+
 	scheme.Name = strings.TrimSuffix(filepath.Base(pth), filepath.Ext(pth))
 	scheme.Path = pth
+	scheme.IsShared, scheme.User = schemeOwner(pth)
 
 	return scheme, nil
 }
 
+// BuildableReferences returns the buildable references of the scheme's build and test actions.
+func (s Scheme) BuildableReferences() []BuildableReference {
+	var references []BuildableReference
+	for _, entry := range s.BuildAction.BuildActionEntries {
+		references = append(references, entry.BuildableReference)
+	}
+	for _, testable := range s.TestAction.Testables {
+		references = append(references, testable.BuildableReference)
+	}
+	return references
+}
+
+// TestCommandLineArguments returns the enabled command line arguments the tests receive:
+// the launch action's arguments if the test action uses them, otherwise the test action's own arguments.
+func (s Scheme) TestCommandLineArguments() []CommandLineArgument {
+	arguments := s.TestAction.CommandLineArguments
+	if s.TestAction.ShouldUseLaunchSchemeArgsEnv == "YES" {
+		arguments = s.LaunchAction.CommandLineArguments
+	}
+
+	var enabled []CommandLineArgument
+	for _, argument := range arguments {
+		if argument.IsEnabled == "YES" {
+			enabled = append(enabled, argument)
+		}
+	}
+	return enabled
+}
+
+// TestEnvironmentVariables returns the enabled environment variables the tests receive:
+// the launch action's variables if the test action uses them, otherwise the test action's own variables.
+func (s Scheme) TestEnvironmentVariables() []EnvironmentVariable {
+	variables := s.TestAction.EnvironmentVariables
+	if s.TestAction.ShouldUseLaunchSchemeArgsEnv == "YES" {
+		variables = s.LaunchAction.EnvironmentVariables
+	}
+
+	var enabled []EnvironmentVariable
+	for _, variable := range variables {
+		if variable.IsEnabled == "YES" {
+			enabled = append(enabled, variable)
+		}
+	}
+	return enabled
+}
+
 // AppBuildActionEntry ...
 func (s Scheme) AppBuildActionEntry() (BuildActionEntry, bool) {
 	var entry BuildActionEntry
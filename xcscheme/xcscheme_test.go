@@ -69,6 +69,19 @@ func TestAppTestActionEntry(t *testing.T) {
 	require.False(t, scheme.TestAction.Testables[1].BuildableReference.IsAppReference())
 }
 
+func TestBuildableReferences(t *testing.T) {
+	var scheme Scheme
+	require.NoError(t, xml.Unmarshal([]byte(schemeContent), &scheme))
+
+	var blueprintIdentifiers []string
+	for _, reference := range scheme.BuildableReferences() {
+		blueprintIdentifiers = append(blueprintIdentifiers, reference.BlueprintIdentifier)
+	}
+	require.Equal(t, 4, len(blueprintIdentifiers))
+	require.Equal(t, []string{"BA3CBE7419F7A93800CED4D5", "BA3CBE9019F7A93900CED4D5"}, blueprintIdentifiers[:2])
+	require.Equal(t, "BA3CBE9019F7A93900CED4D5", blueprintIdentifiers[2])
+}
+
 const schemeContent = `<?xml version="1.0" encoding="UTF-8"?>
 <Scheme
    LastUpgradeVersion = "0800"
@@ -195,3 +208,268 @@ const schemeContent = `<?xml version="1.0" encoding="UTF-8"?>
    </ArchiveAction>
 </Scheme>
 `
+
+func TestOpenScheme_Actions(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "App.xcscheme", fullSchemeContent)
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+
+	require.Equal(t, "1230", scheme.LastUpgradeVersion)
+	require.Equal(t, "1.3", scheme.Version)
+
+	t.Log("build action")
+	{
+		require.Equal(t, "YES", scheme.BuildAction.ParallelizeBuildables)
+		require.Equal(t, []ExecutionAction{{
+			ActionType: ShellScriptExecutionActionType,
+			ActionContent: ActionContent{
+				Title:          "Run Script",
+				ScriptText:     "echo \"pre build\"\n",
+				ShellToExecute: "/bin/sh",
+				EnvironmentBuildable: &BuildableReference{
+					BuildableIdentifier: "primary",
+					BlueprintIdentifier: "7D5B35FB20E28EE80022BAE6",
+					BuildableName:       "App.app",
+					BlueprintName:       "App",
+					ReferencedContainer: "container:App.xcodeproj",
+				},
+			},
+		}}, scheme.BuildAction.PreActions)
+		require.Equal(t, "YES", scheme.BuildAction.BuildActionEntries[0].BuildForRunning)
+		require.Equal(t, "primary", scheme.BuildAction.BuildActionEntries[0].BuildableReference.BuildableIdentifier)
+	}
+
+	t.Log("test action")
+	{
+		require.Equal(t, "Debug", scheme.TestAction.BuildConfiguration)
+		require.Equal(t, "NO", scheme.TestAction.ShouldUseLaunchSchemeArgsEnv)
+		require.Equal(t, "YES", scheme.TestAction.CodeCoverageEnabled)
+		require.Equal(t, "YES", scheme.TestAction.OnlyGenerateCoverageForSpecifiedTargets)
+		require.Equal(t, []TestPlanReference{{Reference: "container:App.xctestplan", Default: "YES"}}, scheme.TestAction.TestPlans)
+		require.Equal(t, "YES", scheme.TestAction.Testables[0].Parallelizable)
+		require.Equal(t, "random", scheme.TestAction.Testables[0].TestExecutionOrdering)
+		require.Equal(t, []Test{{Identifier: "AppTests/testSlow()"}}, scheme.TestAction.Testables[0].SkippedTests)
+		require.Equal(t, []CommandLineArgument{{Argument: "-UITestMode", IsEnabled: "YES"}, {Argument: "-Verbose", IsEnabled: "NO"}}, scheme.TestAction.CommandLineArguments)
+		require.Equal(t, []EnvironmentVariable{{Key: "API_URL", Value: "http://localhost", IsEnabled: "YES"}}, scheme.TestAction.EnvironmentVariables)
+		require.Equal(t, 1, len(scheme.TestAction.CodeCoverageTargets))
+		require.Equal(t, "App", scheme.TestAction.CodeCoverageTargets[0].BlueprintName)
+	}
+
+	t.Log("launch action")
+	{
+		require.Equal(t, "Debug", scheme.LaunchAction.BuildConfiguration)
+		require.Equal(t, "0", scheme.LaunchAction.LaunchStyle)
+		require.NotNil(t, scheme.LaunchAction.BuildableProductRunnable)
+		require.Equal(t, "App.app", scheme.LaunchAction.BuildableProductRunnable.BuildableReference.BuildableName)
+		require.Nil(t, scheme.LaunchAction.RemoteRunnable)
+		require.Equal(t, []CommandLineArgument{{Argument: "-com.apple.CoreData.SQLDebug 1", IsEnabled: "YES"}}, scheme.LaunchAction.CommandLineArguments)
+		require.Equal(t, []EnvironmentVariable{{Key: "OS_ACTIVITY_MODE", Value: "disable", IsEnabled: "YES"}}, scheme.LaunchAction.EnvironmentVariables)
+		require.Equal(t, &LocationScenarioReference{Identifier: "London, England", ReferenceType: "1"}, scheme.LaunchAction.LocationScenarioReference)
+		require.Equal(t, []AdditionalOption{{Key: "NSZombieEnabled", Value: "YES", IsEnabled: "YES"}}, scheme.LaunchAction.AdditionalOptions)
+	}
+
+	t.Log("profile, analyze and archive actions")
+	{
+		require.Equal(t, "Release", scheme.ProfileAction.BuildConfiguration)
+		require.Equal(t, "YES", scheme.ProfileAction.ShouldUseLaunchSchemeArgsEnv)
+		require.Equal(t, "App.app", scheme.ProfileAction.BuildableProductRunnable.BuildableReference.BuildableName)
+		require.Equal(t, "Debug", scheme.AnalyzeAction.BuildConfiguration)
+		require.Equal(t, "Release", scheme.ArchiveAction.BuildConfiguration)
+		require.Equal(t, "YES", scheme.ArchiveAction.RevealArchiveInOrganizer)
+		require.Equal(t, SendEmailExecutionActionType, scheme.ArchiveAction.PostActions[0].ActionType)
+		require.Equal(t, "ci@example.com", scheme.ArchiveAction.PostActions[0].ActionContent.EmailRecipient)
+	}
+
+	require.Equal(t, []CommandLineArgument{{Argument: "-UITestMode", IsEnabled: "YES"}}, scheme.TestCommandLineArguments())
+	require.Equal(t, []EnvironmentVariable{{Key: "API_URL", Value: "http://localhost", IsEnabled: "YES"}}, scheme.TestEnvironmentVariables())
+
+	scheme.TestAction.ShouldUseLaunchSchemeArgsEnv = "YES"
+	require.Equal(t, []CommandLineArgument{{Argument: "-com.apple.CoreData.SQLDebug 1", IsEnabled: "YES"}}, scheme.TestCommandLineArguments())
+	require.Equal(t, []EnvironmentVariable{{Key: "OS_ACTIVITY_MODE", Value: "disable", IsEnabled: "YES"}}, scheme.TestEnvironmentVariables())
+}
+
+const fullSchemeContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1230"
+   version = "1.3">
+   <BuildAction
+      parallelizeBuildables = "YES"
+      buildImplicitDependencies = "YES">
+      <PreActions>
+         <ExecutionAction
+            ActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.ShellScriptAction">
+            <ActionContent
+               title = "Run Script"
+               scriptText = "echo &quot;pre build&quot;&#10;"
+               shellToExecute = "/bin/sh">
+               <EnvironmentBuildable>
+                  <BuildableReference
+                     BuildableIdentifier = "primary"
+                     BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+                     BuildableName = "App.app"
+                     BlueprintName = "App"
+                     ReferencedContainer = "container:App.xcodeproj">
+                  </BuildableReference>
+               </EnvironmentBuildable>
+            </ActionContent>
+         </ExecutionAction>
+      </PreActions>
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "YES"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+               BuildableName = "App.app"
+               BlueprintName = "App"
+               ReferencedContainer = "container:App.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <TestAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      shouldUseLaunchSchemeArgsEnv = "NO"
+      codeCoverageEnabled = "YES"
+      onlyGenerateCoverageForSpecifiedTargets = "YES">
+      <TestPlans>
+         <TestPlanReference
+            reference = "container:App.xctestplan"
+            default = "YES">
+         </TestPlanReference>
+      </TestPlans>
+      <Testables>
+         <TestableReference
+            skipped = "NO"
+            parallelizable = "YES"
+            testExecutionOrdering = "random">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D0342F020F4BA280050B6A6"
+               BuildableName = "AppTests.xctest"
+               BlueprintName = "AppTests"
+               ReferencedContainer = "container:App.xcodeproj">
+            </BuildableReference>
+            <SkippedTests>
+               <Test
+                  Identifier = "AppTests/testSlow()">
+               </Test>
+            </SkippedTests>
+         </TestableReference>
+      </Testables>
+      <CommandLineArguments>
+         <CommandLineArgument
+            argument = "-UITestMode"
+            isEnabled = "YES">
+         </CommandLineArgument>
+         <CommandLineArgument
+            argument = "-Verbose"
+            isEnabled = "NO">
+         </CommandLineArgument>
+      </CommandLineArguments>
+      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "API_URL"
+            value = "http://localhost"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+      <CodeCoverageTargets>
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </CodeCoverageTargets>
+   </TestAction>
+   <LaunchAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      launchStyle = "0"
+      useCustomWorkingDirectory = "NO"
+      ignoresPersistentStateOnLaunch = "NO"
+      debugDocumentVersioning = "YES"
+      debugServiceExtension = "internal"
+      allowLocationSimulation = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+      <CommandLineArguments>
+         <CommandLineArgument
+            argument = "-com.apple.CoreData.SQLDebug 1"
+            isEnabled = "YES">
+         </CommandLineArgument>
+      </CommandLineArguments>
+      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "OS_ACTIVITY_MODE"
+            value = "disable"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+      <LocationScenarioReference
+         identifier = "London, England"
+         referenceType = "1">
+      </LocationScenarioReference>
+      <AdditionalOptions>
+         <AdditionalOption
+            key = "NSZombieEnabled"
+            value = "YES"
+            isEnabled = "YES">
+         </AdditionalOption>
+      </AdditionalOptions>
+   </LaunchAction>
+   <ProfileAction
+      buildConfiguration = "Release"
+      shouldUseLaunchSchemeArgsEnv = "YES"
+      savedToolIdentifier = ""
+      useCustomWorkingDirectory = "NO"
+      debugDocumentVersioning = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </ProfileAction>
+   <AnalyzeAction
+      buildConfiguration = "Debug">
+   </AnalyzeAction>
+   <ArchiveAction
+      buildConfiguration = "Release"
+      revealArchiveInOrganizer = "YES">
+      <PostActions>
+         <ExecutionAction
+            ActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.SendEmailAction">
+            <ActionContent
+               title = "Send Email"
+               emailRecipient = "ci@example.com"
+               emailSubject = "Archived"
+               emailBody = ""
+               attachLogToEmail = "NO">
+            </ActionContent>
+         </ExecutionAction>
+      </PostActions>
+   </ArchiveAction>
+</Scheme>
+`
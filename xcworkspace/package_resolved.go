@@ -0,0 +1,59 @@
+package xcworkspace
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/swiftpm"
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+)
+
+// PackageResolvedPath returns the path of the workspace's Package.resolved file.
+func (w Workspace) PackageResolvedPath() string {
+	return filepath.Join(w.Path, "xcshareddata", "swiftpm", "Package.resolved")
+}
+
+// PackageResolved returns the pinned Swift package versions of the workspace.
+func (w Workspace) PackageResolved() (swiftpm.PackageResolved, error) {
+	return swiftpm.OpenPackageResolved(w.PackageResolvedPath())
+}
+
+// CheckPackageResolved returns the remote packages of the workspace's projects, which are not pinned in the workspace's Package.resolved
+// or which pin does not satisfy the package's requirement.
+// Every remote package is reported if the workspace has no Package.resolved.
+func (w Workspace) CheckPackageResolved() ([]xcodeproj.PackageResolvedMismatch, error) {
+	projectLocations, err := w.ProjectFileLocations()
+	if err != nil {
+		return nil, err
+	}
+
+	var references []xcodeproj.SwiftPackageReference
+	for _, projectLocation := range projectLocations {
+		if exist, err := pathutil.IsPathExists(projectLocation); err != nil {
+			return nil, fmt.Errorf("failed to check if project exist at: %s, error: %s", projectLocation, err)
+		} else if !exist {
+			continue
+		}
+
+		project, err := xcodeproj.Open(projectLocation)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, project.Proj.SwiftPackageReferences()...)
+	}
+	if len(references) == 0 {
+		return nil, nil
+	}
+
+	var resolved swiftpm.PackageResolved
+	if exist, err := pathutil.IsPathExists(w.PackageResolvedPath()); err != nil {
+		return nil, err
+	} else if exist {
+		if resolved, err = w.PackageResolved(); err != nil {
+			return nil, err
+		}
+	}
+
+	return xcodeproj.CheckPackageResolved(references, resolved)
+}
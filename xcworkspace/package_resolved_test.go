@@ -0,0 +1,99 @@
+package xcworkspace
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspace_CheckPackageResolved(t *testing.T) {
+	dir, err := ioutil.TempDir("", "package-resolved")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	testhelper.CreateFileInDir(t, dir, "App.xcworkspace/contents.xcworkspacedata", `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:App.xcodeproj">
+   </FileRef>
+</Workspace>
+`)
+	testhelper.CreateFileInDir(t, dir, "App.xcodeproj/project.pbxproj", testhelper.XcodeProjectTest)
+
+	project, err := xcodeproj.Open(filepath.Join(dir, "App.xcodeproj"))
+	require.NoError(t, err)
+	_, err = project.AddRemoteSwiftPackage("https://github.com/Alamofire/Alamofire.git", xcodeproj.SwiftPackageRequirement{
+		Kind:           xcodeproj.UpToNextMajorVersionRequirementKind,
+		MinimumVersion: "5.9.0",
+	})
+	require.NoError(t, err)
+	require.NoError(t, project.Save())
+
+	workspace, err := Open(filepath.Join(dir, "App.xcworkspace"))
+	require.NoError(t, err)
+
+	t.Log("no Package.resolved")
+	{
+		mismatches, err := workspace.CheckPackageResolved()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(mismatches))
+		require.Equal(t, "package is not pinned", mismatches[0].Reason)
+	}
+
+	t.Log("stale Package.resolved")
+	{
+		testhelper.CreateFileInDir(t, dir, "App.xcworkspace/xcshareddata/swiftpm/Package.resolved", `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : {
+        "revision" : "3dc6a42c7727c49bf26508e29b0a0b35f9c7e1ad",
+        "version" : "5.8.1"
+      }
+    }
+  ],
+  "version" : 2
+}
+`)
+		mismatches, err := workspace.CheckPackageResolved()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(mismatches))
+		require.Equal(t, "pinned version 5.8.1 does not satisfy the requirement upToNextMajorVersion from 5.9.0", mismatches[0].Reason)
+	}
+
+	t.Log("up to date Package.resolved")
+	{
+		testhelper.CreateFileInDir(t, dir, "App.xcworkspace/xcshareddata/swiftpm/Package.resolved", `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : {
+        "revision" : "f455c2975872ccd2d9c81594c658af65716e9b9a",
+        "version" : "5.9.1"
+      }
+    }
+  ],
+  "version" : 2
+}
+`)
+		mismatches, err := workspace.CheckPackageResolved()
+		require.NoError(t, err)
+		require.Equal(t, 0, len(mismatches))
+
+		resolved, err := workspace.PackageResolved()
+		require.NoError(t, err)
+		require.Equal(t, "5.9.1", resolved.Pins[0].State.Version)
+	}
+}
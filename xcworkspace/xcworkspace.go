@@ -22,6 +22,9 @@ type Workspace struct {
 
 	Name string
 	Path string
+
+	// BuildSettingsBackend selects how SchemeBuildSettings computes the build settings, defaults to xcodeproj.XcodebuildBuildSettingsBackend.
+	BuildSettingsBackend xcodeproj.BuildSettingsBackend
 }
 
 // Scheme returns the scheme by name and it's container's absolute path.
@@ -45,9 +48,52 @@ func (w Workspace) Scheme(name string) (*xcscheme.Scheme, string, error) {
 
 // SchemeBuildSettings ...
 func (w Workspace) SchemeBuildSettings(scheme, configuration string, customOptions ...string) (serialized.Object, error) {
+	if w.BuildSettingsBackend == xcodeproj.OfflineBuildSettingsBackend {
+		return w.OfflineSchemeBuildSettings(scheme, configuration, customOptions...)
+	}
 	return xcodebuild.ShowWorkspaceBuildSettings(w.Path, scheme, configuration, customOptions...)
 }
 
+// OfflineSchemeBuildSettings returns the build settings of the scheme's main buildable target without calling xcodebuild.
+// The main buildable is the scheme's app build action entry or the first build action entry if the scheme builds no app.
+// If configuration is empty, the scheme's launch action configuration is used, like xcodebuild does.
+// See xcodeproj.XcodeProj.OfflineTargetBuildSettings for the supported customOptions.
+func (w Workspace) OfflineSchemeBuildSettings(scheme, configuration string, customOptions ...string) (serialized.Object, error) {
+	s, container, err := w.Scheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if configuration == "" {
+		configuration = s.LaunchAction.BuildConfiguration
+	}
+
+	entry, ok := s.AppBuildActionEntry()
+	if !ok {
+		if len(s.BuildAction.BuildActionEntries) == 0 {
+			return nil, fmt.Errorf("scheme (%s) has no build action entry", scheme)
+		}
+		entry = s.BuildAction.BuildActionEntries[0]
+	}
+
+	projectPth, err := entry.BuildableReference.ReferencedContainerAbsPath(filepath.Dir(container))
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := xcodeproj.Open(projectPth)
+	if err != nil {
+		return nil, err
+	}
+
+	target, ok := project.Proj.Target(entry.BuildableReference.BlueprintIdentifier)
+	if !ok {
+		return nil, fmt.Errorf("could not find target (%s) in project (%s)", entry.BuildableReference.BlueprintIdentifier, projectPth)
+	}
+
+	return project.OfflineTargetBuildSettings(target.Name, configuration, customOptions...)
+}
+
 // Schemes ...
 func (w Workspace) Schemes() (map[string][]xcscheme.Scheme, error) {
 	schemesByContainer := map[string][]xcscheme.Scheme{}
@@ -88,6 +88,60 @@ func TestSchemes(t *testing.T) {
 	}
 }
 
+func TestWorkspace_OfflineSchemeBuildSettings(t *testing.T) {
+	dir := t.TempDir()
+	testhelper.CreateFileInDir(t, dir, "App.xcworkspace/contents.xcworkspacedata", `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:App.xcodeproj">
+   </FileRef>
+</Workspace>
+`)
+	testhelper.CreateFileInDir(t, dir, "App.xcodeproj/project.pbxproj", testhelper.XcodeProjectTest)
+	testhelper.CreateFileInDir(t, dir, "App.xcworkspace/xcshareddata/xcschemes/App.xcscheme", `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "0940"
+   version = "1.3">
+   <BuildAction>
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForArchiving = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "7D5B35FB20E28EE80022BAE6"
+               BuildableName = "XcodeProj.app"
+               BlueprintName = "XcodeProj"
+               ReferencedContainer = "container:App.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <LaunchAction
+      buildConfiguration = "Debug">
+   </LaunchAction>
+</Scheme>
+`)
+
+	workspace, err := Open(filepath.Join(dir, "App.xcworkspace"))
+	require.NoError(t, err)
+
+	t.Log("scheme's launch action configuration")
+	{
+		buildSettings, err := workspace.OfflineSchemeBuildSettings("App", "")
+		require.NoError(t, err)
+		require.Equal(t, "Debug", buildSettings["CONFIGURATION"])
+	}
+
+	t.Log("explicit configuration")
+	{
+		buildSettings, err := workspace.OfflineSchemeBuildSettings("App", "Release")
+		require.NoError(t, err)
+		require.Equal(t, "Release", buildSettings["CONFIGURATION"])
+	}
+}
+
 func TestWorkspaceFileLocations(t *testing.T) {
 	workspaceContentsPth := testhelper.CreateTmpFile(t, "contents.xcworkspacedata", workspaceContentsContent)
 	workspacePth := filepath.Dir(workspaceContentsPth)